@@ -0,0 +1,159 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DefaultBindTag default tag name for bind data to struct
+const DefaultBindTag = "json"
+
+// binder bind map data to struct by reflection.
+type binder struct {
+	// tag name for get the field name
+	tagName string
+}
+
+// Bind the data to struct pointer.
+func (b *binder) Bind(data map[string]any, ptr any) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("filter: bind data requires a non-nil struct pointer, given %T", ptr)
+	}
+
+	return b.bindStruct(data, rv.Elem(), "")
+}
+
+func (b *binder) bindStruct(data map[string]any, rv reflect.Value, path string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+
+		name := sf.Name
+		if tagVal := sf.Tag.Get(b.tagName); tagVal != "" {
+			if tagName := strings.SplitN(tagVal, ",", 2)[0]; tagName == "-" {
+				continue
+			} else if tagName != "" {
+				name = tagName
+			}
+		} else if sf.Anonymous { // embedded struct without tag name
+			fv := rv.Field(i)
+			if fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Struct {
+				if fv.IsNil() {
+					if !fv.CanSet() {
+						continue
+					}
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+
+			if fv.Kind() == reflect.Struct {
+				if err := b.bindStruct(data, fv, path); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		val, ok := lookupKey(data, name)
+		if !ok || val == nil {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if !fv.CanSet() {
+			continue
+		}
+
+		if err := b.setValue(fv, val, path+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *binder) setValue(fv reflect.Value, val any, path string) error {
+	ft := fv.Type()
+	switch ft.Kind() {
+	case reflect.Ptr:
+		elemTyp := ft.Elem()
+		if elemTyp.Kind() == reflect.Struct && elemTyp != timeType {
+			if mp, ok := val.(map[string]any); ok {
+				if fv.IsNil() {
+					fv.Set(reflect.New(elemTyp))
+				}
+				return b.bindStruct(mp, fv.Elem(), path+".")
+			}
+		}
+	case reflect.Struct:
+		if ft != timeType {
+			if mp, ok := val.(map[string]any); ok {
+				return b.bindStruct(mp, fv, path+".")
+			}
+		}
+	case reflect.Slice:
+		elemTyp := ft.Elem()
+		if elemTyp.Kind() == reflect.Ptr {
+			elemTyp = elemTyp.Elem()
+		}
+
+		if elemTyp.Kind() == reflect.Struct && elemTyp != timeType {
+			srcRv := reflect.ValueOf(val)
+			if srcRv.Kind() != reflect.Slice {
+				break
+			}
+
+			newSl := reflect.MakeSlice(ft, srcRv.Len(), srcRv.Len())
+			for i := 0; i < srcRv.Len(); i++ {
+				elem := srcRv.Index(i).Interface()
+				if err := b.setValue(newSl.Index(i), elem, fmt.Sprintf("%s.%d", path, i)); err != nil {
+					return err
+				}
+			}
+
+			fv.Set(newSl)
+			return nil
+		}
+	case reflect.Map:
+		if mp, ok := val.(map[string]any); ok && ft.Key().Kind() == reflect.String {
+			newMp := reflect.MakeMapWithSize(ft, len(mp))
+			for k, v := range mp {
+				nv, err := convType(v, ft.Elem())
+				if err != nil {
+					return fmt.Errorf("filter: bind field '%s.%s' error: %w", path, k, err)
+				}
+				newMp.SetMapIndex(reflect.ValueOf(k).Convert(ft.Key()), nv)
+			}
+
+			fv.Set(newMp)
+			return nil
+		}
+	}
+
+	nv, err := convType(val, ft)
+	if err != nil {
+		return fmt.Errorf("filter: bind field '%s' error: %w", path, err)
+	}
+
+	fv.Set(nv)
+	return nil
+}
+
+// lookupKey find value by key, will try case-insensitive match on not found.
+func lookupKey(data map[string]any, key string) (any, bool) {
+	if val, ok := data[key]; ok {
+		return val, true
+	}
+
+	for k, val := range data {
+		if strings.EqualFold(k, key) {
+			return val, true
+		}
+	}
+	return nil, false
+}
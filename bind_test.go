@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+type bindBase struct {
+	ID int `json:"id"`
+}
+
+type bindUser struct {
+	bindBase
+	Name     string            `json:"name" mapstructure:"user_name"`
+	Age      int               `json:"age"`
+	Ok       bool              `json:"ok"`
+	Birthday time.Time         `json:"birthday"`
+	Tags     []string          `json:"tags"`
+	Scores   map[string]int    `json:"scores"`
+	Home     *bindAddress      `json:"home"`
+	Works    []bindAddress     `json:"works"`
+	Extra    map[string]string `json:"-"`
+}
+
+type bindAddress struct {
+	City string
+}
+
+func TestFiltration_BindStruct(t *testing.T) {
+	is := assert.New(t)
+
+	f := New(map[string]any{
+		"id":       " 23",
+		"name":     " inhere ",
+		"age":      "34",
+		"ok":       "yes",
+		"birthday": "2018-10-16 12:34",
+		"tags":     "go,php",
+		"scores":   map[string]any{"go": "90"},
+		"home":     map[string]any{"city": "shen zhen"},
+		"works":    []any{map[string]any{"City": "a"}, map[string]any{"city": "b"}},
+	})
+	f.AddRules(map[string]string{
+		"id":       "trim|int",
+		"name":     "trim|ucFirst",
+		"birthday": "strToTime",
+	})
+	is.NoErr(f.Filtering())
+
+	u := &bindUser{}
+	is.NoErr(f.BindStruct(u))
+	is.Eq(23, u.ID)
+	is.Eq("Inhere", u.Name)
+	is.Eq(0, u.Age)
+	is.Eq(2018, u.Birthday.Year())
+	is.Nil(u.Home)
+
+	// bind raw data
+	f.WithOptions(func(opt *Options) {
+		opt.BindRaw = true
+	})
+	u = &bindUser{}
+	is.NoErr(f.BindStruct(u))
+	is.Eq("Inhere", u.Name)
+	is.Eq(34, u.Age)
+	is.True(u.Ok)
+	is.Eq([]string{"go", "php"}, u.Tags)
+	is.Eq(map[string]int{"go": 90}, u.Scores)
+	is.Eq("shen zhen", u.Home.City)
+	is.Len(u.Works, 2)
+	is.Eq("b", u.Works[1].City)
+	is.Nil(u.Extra)
+
+	// bind raw data with the nested field rule
+	type bindProfile struct {
+		User struct {
+			Name string `json:"name"`
+			Age  int    `json:"age"`
+		} `json:"user"`
+	}
+	f = New(map[string]any{
+		"user": map[string]any{"name": " tom ", "age": "23"},
+	}).WithOptions(func(opt *Options) {
+		opt.BindRaw = true
+	})
+	f.AddRule("user.name", "trim")
+	is.NoErr(f.Filtering())
+	p := &bindProfile{}
+	is.NoErr(f.BindStruct(p))
+	is.Eq("tom", p.User.Name)
+	is.Eq(23, p.User.Age)
+
+	// custom tag name
+	f = New(map[string]any{"user_name": " tom "})
+	f.AddRule("user_name", "trim")
+	f.WithOptions(func(opt *Options) {
+		opt.BindTag = "mapstructure"
+	})
+	is.NoErr(f.Filtering())
+	u = &bindUser{}
+	is.NoErr(f.BindStruct(u))
+	is.Eq("tom", u.Name)
+
+	// error
+	is.Err(f.BindStruct(nil))
+	is.Err(f.BindStruct(*u))
+
+	f = New(map[string]any{"age": "abc"})
+	f.AddRule("age", "trim")
+	is.NoErr(f.Filtering())
+	err := f.BindStruct(u)
+	is.ErrMsg(err, `filter: bind field 'age' error: cannot convert string to int: strconv.ParseInt: parsing "abc": invalid syntax`)
+}
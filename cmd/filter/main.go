@@ -0,0 +1,489 @@
+// Command filter filters the JSON, NDJSON and CSV records by the filter rules.
+//
+// Usage:
+//
+//	filter [options] [file ...]
+//
+// The records are read from the files, or stdin on no files or the file is "-".
+// The cleaned records are written to stdout in the same format as the input.
+//
+// Examples:
+//
+//	cat users.ndjson | filter -r 'name=trim|ucFirst' -r 'age=int'
+//	filter -s rules.yaml --errors=annotate users.csv
+//
+// Exit codes: 0 on success, 1 on filtering or IO failed, 2 on invalid options.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/gookit/filter"
+)
+
+// input formats
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+)
+
+// the behaviors on filtering a record failed
+const (
+	// errorsSkip skip the record, the error will be reported to stderr
+	errorsSkip = "skip"
+	// errorsFail stop on the first failed record and exit with code 1
+	errorsFail = "fail"
+	// errorsAnnotate write the record with the errors in the errorsKey field
+	errorsAnnotate = "annotate"
+)
+
+// errorsKey the field name of the errors on the errors mode is annotate
+const errorsKey = "_errors"
+
+// ruleFlags the repeated rule options. eg: -r 'name=trim' -r 'age=int'
+type ruleFlags []string
+
+func (rs *ruleFlags) String() string {
+	return strings.Join(*rs, ", ")
+}
+
+func (rs *ruleFlags) Set(s string) error {
+	if field, _, ok := strings.Cut(s, "="); !ok || strings.TrimSpace(field) == "" {
+		return fmt.Errorf("invalid rule %q, expect 'field=rule'", s)
+	}
+
+	*rs = append(*rs, s)
+	return nil
+}
+
+// app the filter command
+type app struct {
+	schema *filter.Schema
+	// input format, detect by the file ext or content on it is empty.
+	format string
+	// the errors mode
+	errors string
+
+	out    *bufio.Writer
+	stderr io.Writer
+	// number of the read records
+	num int
+	// the CSV writer and columns, they are shared by all inputs.
+	csvOut  *csv.Writer
+	columns []string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run the command, returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var rules ruleFlags
+	fs := flag.NewFlagSet("filter", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Var(&rules, "r", "the filter `rule` for a field, can be repeated. eg: 'name=trim|ucFirst'")
+	schemaFile := fs.String("s", "", "load the rules from the JSON or YAML schema `file`")
+	format := fs.String("f", "", "the input `format`: json, ndjson or csv. default detect by the file ext or content")
+	mode := fs.String("errors", errorsFail, "the `mode` on a record filtering failed: skip, fail or annotate")
+	output := fs.String("o", "", "write the output to the `file`, default is stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: filter [options] [file ...]")
+		fmt.Fprintln(stderr, "\nFilter the JSON, NDJSON and CSV records by the rules, read from stdin on no files.")
+		fmt.Fprintln(stderr, "\nOptions:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	schema, err := checkOptions(rules, *schemaFile, *format, *mode)
+	if err != nil {
+		printError(stderr, err)
+		return 2
+	}
+
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			printError(stderr, err)
+			return 1
+		}
+		defer file.Close()
+		stdout = file
+	}
+
+	a := &app{
+		schema: schema,
+		format: *format,
+		errors: *mode,
+		out:    bufio.NewWriter(stdout),
+		stderr: stderr,
+	}
+
+	inputs := fs.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+
+	for _, name := range inputs {
+		if err = a.processFile(name, stdin); err != nil {
+			break
+		}
+	}
+
+	// write the processed records on error
+	if ferr := a.out.Flush(); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		printError(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// printError print the error with the command name, the errors of the
+// filter package have the same prefix.
+func printError(w io.Writer, err error) {
+	fmt.Fprintln(w, "filter:", strings.TrimPrefix(err.Error(), "filter: "))
+}
+
+// checkOptions check the options and load the schema.
+func checkOptions(rules []string, schemaFile, format, mode string) (*filter.Schema, error) {
+	switch format {
+	case "", formatJSON, formatNDJSON, formatCSV:
+	default:
+		return nil, fmt.Errorf("invalid format %q, allow: json, ndjson, csv", format)
+	}
+
+	switch mode {
+	case errorsSkip, errorsFail, errorsAnnotate:
+	default:
+		return nil, fmt.Errorf("invalid errors mode %q, allow: skip, fail, annotate", mode)
+	}
+
+	if len(rules) > 0 && schemaFile != "" {
+		return nil, errors.New("the -r and -s options cannot be used together")
+	}
+
+	var src io.Reader
+	switch {
+	case len(rules) > 0:
+		src = bytes.NewReader(rulesDoc(rules))
+	case schemaFile != "":
+		file, err := os.Open(schemaFile)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		src = file
+	default:
+		return nil, errors.New("no rules, please use the -r or -s option")
+	}
+
+	return filter.LoadSchema(src, filter.CollectErrors)
+}
+
+// rulesDoc convert the rule options to a JSON schema document, the
+// field order is kept. the rules of the same field will be joined by '|'
+func rulesDoc(rules []string) []byte {
+	var fields []string
+	fieldRules := make(map[string]string, len(rules))
+	for _, s := range rules {
+		field, rule, _ := strings.Cut(s, "=")
+		field = strings.TrimSpace(field)
+
+		if old, ok := fieldRules[field]; ok {
+			fieldRules[field] = old + "|" + rule
+		} else {
+			fields = append(fields, field)
+			fieldRules[field] = rule
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(field)
+		val, _ := json.Marshal(fieldRules[field])
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// processFile process the records of the file, "-" is the stdin.
+func (a *app) processFile(name string, stdin io.Reader) error {
+	var r io.Reader = stdin
+	if name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	br := bufio.NewReader(r)
+	format := a.format
+	if format == "" {
+		format = detectFormat(name, br)
+	}
+
+	var err error
+	if format == formatCSV {
+		err = a.processCSV(br)
+	} else {
+		err = a.processJSON(br)
+	}
+
+	if err != nil && name != "-" {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return err
+}
+
+// detectFormat detect the input format by the file ext, then the first non-space char.
+func detectFormat(name string, br *bufio.Reader) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return formatJSON
+	case ".ndjson", ".jsonl":
+		return formatNDJSON
+	case ".csv":
+		return formatCSV
+	}
+
+	// the error is ignored, it will be returned on read
+	bs, _ := br.Peek(512)
+	if s := bytes.TrimSpace(bs); len(s) > 0 && s[0] != '{' && s[0] != '[' {
+		return formatCSV
+	}
+	return formatJSON
+}
+
+// filterRecord filter the record by the errors mode. returns nil on the record is skipped.
+func (a *app) filterRecord(data map[string]any) (map[string]any, error) {
+	a.num++
+	f := a.schema.New(data)
+	err := f.Filtering()
+	if err == nil {
+		return f.MergedData(), nil
+	}
+
+	// the rules or context error, it is not the record error
+	var es filter.Errors
+	if !errors.As(err, &es) || a.errors == errorsFail {
+		return nil, fmt.Errorf("record #%d: %w", a.num, err)
+	}
+
+	if a.errors == errorsSkip {
+		fmt.Fprintf(a.stderr, "filter: skip record #%d: %s\n", a.num, err)
+		return nil, nil
+	}
+
+	out := f.MergedData()
+	out[errorsKey] = es.All()
+	return out, nil
+}
+
+// processJSON process the JSON document(s), the document can be an object or
+// an array of objects. the NDJSON is a stream of the object documents.
+func (a *app) processJSON(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("decode JSON error: %w", err)
+		}
+
+		if raw[0] != '[' {
+			bs, err := a.processObject(raw)
+			if err != nil {
+				return err
+			}
+			if bs != nil {
+				a.out.Write(bs)
+				a.out.WriteByte('\n')
+			}
+			continue
+		}
+
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return fmt.Errorf("decode JSON error: %w", err)
+		}
+
+		// keep the array format on output
+		sep := "[\n"
+		for _, item := range items {
+			bs, err := a.processObject(item)
+			if err != nil {
+				return err
+			}
+			if bs != nil {
+				a.out.WriteString(sep)
+				a.out.Write(bs)
+				sep = ",\n"
+			}
+		}
+
+		if sep == "[\n" {
+			a.out.WriteString("[]\n")
+		} else {
+			a.out.WriteString("\n]\n")
+		}
+	}
+}
+
+// processObject filter a JSON object record, returns the encoded
+// cleaned record. returns nil on the record is skipped.
+func (a *app) processObject(raw json.RawMessage) ([]byte, error) {
+	if raw[0] != '{' {
+		return nil, fmt.Errorf("record #%d: expect a JSON object, but got %s", a.num+1, raw)
+	}
+
+	// the numbers are decoded to int, uint64 or float64, same as the
+	// filter.FromJSON. the large numbers are kept exactly.
+	f, err := filter.FromJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("record #%d: %w", a.num+1, err)
+	}
+
+	out, err := a.filterRecord(f.RawData())
+	if err != nil || out == nil {
+		return nil, err
+	}
+
+	bs, err := encodeJSON(out)
+	if err != nil {
+		return nil, fmt.Errorf("record #%d: encode JSON error: %w", a.num, err)
+	}
+	return bs, nil
+}
+
+// processCSV process the CSV records, the first row is the header.
+//
+// The output columns are the header columns, and the errorsKey column on the
+// errors mode is annotate. all inputs should have same header.
+func (a *app) processCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return fmt.Errorf("read CSV error: %w", err)
+	}
+
+	columns := header
+	if a.errors == errorsAnnotate {
+		columns = append(columns[:len(columns):len(columns)], errorsKey)
+	}
+
+	if a.csvOut == nil {
+		a.csvOut = csv.NewWriter(a.out)
+		a.columns = columns
+		if err = a.csvOut.Write(columns); err != nil {
+			return err
+		}
+	} else if strings.Join(columns, ",") != strings.Join(a.columns, ",") {
+		return fmt.Errorf("the CSV header %q is different from the first input", header)
+	}
+
+	for {
+		record, err := cr.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("read CSV error: %w", err)
+		}
+
+		data := make(map[string]any, len(header))
+		for i, col := range header {
+			data[col] = record[i]
+		}
+
+		out, err := a.filterRecord(data)
+		if err != nil {
+			return err
+		}
+		if out == nil {
+			continue
+		}
+
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			if row[i], err = csvValue(out[col]); err != nil {
+				return fmt.Errorf("record #%d: %w", a.num, err)
+			}
+		}
+		if err = a.csvOut.Write(row); err != nil {
+			return err
+		}
+	}
+
+	a.csvOut.Flush()
+	return a.csvOut.Error()
+}
+
+// csvValue convert the value to CSV field, the slice and map will be encoded as JSON.
+func csvValue(val any) (string, error) {
+	switch tv := val.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return tv, nil
+	case map[string][]string:
+		// the annotated errors
+		if len(tv) == 0 {
+			return "", nil
+		}
+	}
+
+	if kind := reflect.TypeOf(val).Kind(); kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map {
+		bs, err := encodeJSON(val)
+		return string(bs), err
+	}
+	return filter.MustString(val), nil
+}
+
+// encodeJSON encode the value to JSON, the HTML chars are not escaped.
+func encodeJSON(val any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(val); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
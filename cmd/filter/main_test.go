@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+// run the command with the stdin, returns the exit code, stdout and stderr.
+func runCmd(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_json(t *testing.T) {
+	is := assert.New(t)
+	rules := []string{"-r", "name=trim|ucFirst", "-r", "age=int"}
+
+	// NDJSON
+	code, out, _ := runCmd(`{"name": " tom ", "age": "23", "id": 12345678901234567}
+{"name": "<b>", "age": 34}
+`, rules...)
+	is.Eq(0, code)
+	is.Eq(`{"age":23,"id":12345678901234567,"name":"Tom"}
+{"age":34,"name":"<b>"}
+`, out)
+
+	// array
+	code, out, _ = runCmd(`[{"name": " tom "}, {"name": "bob", "tags": ["a"]}]`, rules...)
+	is.Eq(0, code)
+	is.Eq("[\n{\"name\":\"Tom\"},\n{\"name\":\"Bob\",\"tags\":[\"a\"]}\n]\n", out)
+
+	code, out, _ = runCmd(`[]`, rules...)
+	is.Eq(0, code)
+	is.Eq("[]\n", out)
+
+	// the rules of same field are joined
+	code, out, _ = runCmd(`{"name": " TOM "}`, "-r", "name=trim", "-r", "name=lower")
+	is.Eq(0, code)
+	is.Eq(`{"name":"tom"}`+"\n", out)
+
+	// the numeric fields
+	code, out, _ = runCmd(`{"n": 12.5, "s": " 5 ", "id": 12345678901234567890, "big": 123456789012345678901234}`,
+		"-r", "n=int", "-r", "s=trim|int", "-r", "id=uint")
+	is.Eq(0, code)
+	is.Eq(`{"big":123456789012345678901234,"id":12345678901234567890,"n":12,"s":5}`+"\n", out)
+
+	code, _, errOut := runCmd(`{"s": 5}`, "-r", "s=trim")
+	is.Eq(1, code)
+	is.StrContains(errOut, "only use for string type, input int")
+
+	// invalid input
+	code, _, errOut = runCmd(`{"name": `, rules...)
+	is.Eq(1, code)
+	is.StrContains(errOut, "filter: decode JSON error")
+
+	code, _, errOut = runCmd(`{"name": "a"} "abc"`, rules...)
+	is.Eq(1, code)
+	is.StrContains(errOut, `filter: record #2: expect a JSON object, but got "abc"`)
+}
+
+func TestRun_errors(t *testing.T) {
+	is := assert.New(t)
+	in := `{"name": "tom", "age": "23"}
+{"name": " bob ", "age": "abc"}
+{"name": "amy", "age": "34"}
+`
+	rules := []string{"-r", "name=trim", "-r", "age=int"}
+
+	// fail, the processed records are written
+	code, out, errOut := runCmd(in, rules...)
+	is.Eq(1, code)
+	is.Eq(`{"age":23,"name":"tom"}`+"\n", out)
+	is.StrContains(errOut, "filter: record #2: filter: field 'age' apply 'int' error")
+
+	// skip
+	code, out, errOut = runCmd(in, append(rules, "--errors=skip")...)
+	is.Eq(0, code)
+	is.Eq(`{"age":23,"name":"tom"}
+{"age":34,"name":"amy"}
+`, out)
+	is.StrContains(errOut, "filter: skip record #2: filter: field 'age' apply 'int' error")
+
+	// annotate
+	code, out, _ = runCmd(in, append(rules, "-errors", "annotate")...)
+	is.Eq(0, code)
+	is.StrContains(out, `{"_errors":{"age":["strconv.Atoi: parsing \"abc\": invalid syntax"]},"age":"abc","name":"bob"}`)
+	is.StrContains(out, `{"age":34,"name":"amy"}`)
+}
+
+func TestRun_csv(t *testing.T) {
+	is := assert.New(t)
+	in := "name,age,tags\n tom ,23,\"a,b\"\nbob,abc,c\n"
+	rules := []string{"-r", "name=trim|ucFirst", "-r", "age=int", "-r", "tags=str2arr:,"}
+
+	code, out, _ := runCmd(in, append(rules, "--errors=annotate")...)
+	is.Eq(0, code)
+	is.Eq(`name,age,tags,_errors
+Tom,23,"[""a"",""b""]",
+Bob,abc,"[""c""]","{""age"":[""strconv.Atoi: parsing \""abc\"": invalid syntax""]}"
+`, out)
+
+	code, out, _ = runCmd(in, append(rules, "-f", "csv", "--errors=skip")...)
+	is.Eq(0, code)
+	is.Eq("name,age,tags\nTom,23,\"[\"\"a\"\",\"\"b\"\"]\"\n", out)
+
+	// empty input
+	code, out, _ = runCmd("", append(rules, "-f", "csv")...)
+	is.Eq(0, code)
+	is.Eq("", out)
+}
+
+func TestRun_files(t *testing.T) {
+	is := assert.New(t)
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		is.NoErr(os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	schema := write("rules.yaml", "name: trim|upper\nage:\n  filters: int\n  default: 18\n")
+	a := write("a.csv", "name,age\n tom ,23\n")
+	b := write("b.csv", "name,age\namy,\n")
+	c := write("c.csv", "age,name\n1,a\n")
+	d := write("d.ndjson", `{"name": " bob "}`)
+	out := filepath.Join(dir, "out.csv")
+
+	code, _, errOut := runCmd("", "-s", schema, "-o", out, a, b)
+	is.Eq(0, code, errOut)
+	bs, err := os.ReadFile(out)
+	is.NoErr(err)
+	is.Eq("name,age\nTOM,23\nAMY,0\n", string(bs))
+
+	// the header is different
+	code, _, errOut = runCmd("", "-s", schema, a, c)
+	is.Eq(1, code)
+	is.StrContains(errOut, "c.csv: the CSV header [\"age\" \"name\"] is different from the first input")
+
+	// stdin and file
+	code, stdout, _ := runCmd(`{"name": " tom ", "age": "2"}`, "-s", schema, "-", d)
+	is.Eq(0, code)
+	is.Eq(`{"age":2,"name":"TOM"}
+{"age":18,"name":"BOB"}
+`, stdout)
+
+	code, _, errOut = runCmd("", "-s", schema, filepath.Join(dir, "not-exists.json"))
+	is.Eq(1, code)
+	is.StrContains(errOut, "not-exists.json: no such file or directory")
+}
+
+func TestRun_options(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{nil, "filter: no rules, please use the -r or -s option"},
+		{[]string{"-r", "trim"}, `invalid rule "trim", expect 'field=rule'`},
+		{[]string{"-r", "name=trm"}, "filter: unknown filter 'trm', did you mean 'trim'?"},
+		{[]string{"-r", "name=trim", "-s", "rules.json"}, "the -r and -s options cannot be used together"},
+		{[]string{"-r", "name=trim", "-f", "xml"}, `invalid format "xml"`},
+		{[]string{"-r", "name=trim", "--errors", "ignore"}, `invalid errors mode "ignore"`},
+		{[]string{"-s", "not-exists.yaml"}, "not-exists.yaml: no such file or directory"},
+		{[]string{"-unknown"}, "flag provided but not defined: -unknown"},
+	}
+	for _, tt := range tests {
+		code, _, errOut := runCmd("", tt.args...)
+		is.Eq(2, code)
+		is.StrContains(errOut, tt.err)
+	}
+
+	code, _, errOut := runCmd("", "-h")
+	is.Eq(0, code)
+	is.StrContains(errOut, "Usage: filter [options] [file ...]")
+}
@@ -0,0 +1,103 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// condStepName the filter name of the cond step in rule string. eg: "if:notEmpty(trim|lower)"
+const condStepName = "if"
+
+// CondFunc definition. check the value, returns true to apply the cond filters.
+//
+// args are the parsed condition args. f is the current Filtration,
+// it will be nil on filtering struct by Struct().
+type CondFunc func(val any, args []string, f *Filtration) bool
+
+// built-in and custom conditions
+var conditions = map[string]CondFunc{
+	"empty": func(val any, _ []string, _ *Filtration) bool {
+		return isEmpty(val)
+	},
+	"notEmpty": func(val any, _ []string, _ *Filtration) bool {
+		return !isEmpty(val)
+	},
+	"isString": func(val any, _ []string, _ *Filtration) bool {
+		_, ok := val.(string)
+		return ok
+	},
+	"isNumeric": func(val any, _ []string, _ *Filtration) bool {
+		return isNumeric(val)
+	},
+	"isSlice": func(val any, _ []string, _ *Filtration) bool {
+		return isSlice(val)
+	},
+	"isMap": func(val any, _ []string, _ *Filtration) bool {
+		return val != nil && reflect.TypeOf(val).Kind() == reflect.Map
+	},
+	// eq:value check the value is equals to the arg
+	"eq": func(val any, args []string, _ *Filtration) bool {
+		return len(args) > 0 && MustString(val) == args[0]
+	},
+	// fieldEq:field,value check other field value is equals to the arg
+	"fieldEq": func(_ any, args []string, f *Filtration) bool {
+		if f == nil || len(args) < 2 {
+			return false
+		}
+
+		val, ok := f.Get(args[0])
+		return ok && MustString(val) == args[1]
+	},
+}
+
+// AddCondition add a custom condition for use in the rule string.
+//
+// Usage:
+//
+//	filter.AddCondition("isAdmin", func(val any, args []string, f *filter.Filtration) bool {
+//		return f != nil && f.String("role") == "admin"
+//	})
+//	f.AddRule("name", "if:isAdmin(trim|upper)")
+func AddCondition(name string, fn CondFunc) {
+	if name == "" || fn == nil {
+		panic("filter: the condition name and func cannot be empty")
+	}
+	conditions[name] = fn
+}
+
+// checkCond check the condition name is exists
+func checkCond(name string) error {
+	if _, ok := conditions[name]; ok {
+		return nil
+	}
+	return fmt.Errorf("%w condition '%s'", ErrUnknownFilter, name)
+}
+
+// isEmpty check the value is nil, empty string, empty slice or map.
+func isEmpty(val any) bool {
+	if val == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// isNumeric check the value is number or numeric string
+func isNumeric(val any) bool {
+	switch tv := val.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	case string:
+		_, err := strconv.ParseFloat(Trim(tv), 64)
+		return err == nil
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConditions(t *testing.T) {
+	is := assert.New(t)
+
+	is.True(conditions["empty"]("", nil, nil))
+	is.True(conditions["empty"](nil, nil, nil))
+	is.True(conditions["empty"]([]string{}, nil, nil))
+	is.False(conditions["empty"](0, nil, nil))
+	is.True(conditions["notEmpty"]("a", nil, nil))
+	is.True(conditions["isString"]("a", nil, nil))
+	is.False(conditions["isString"](1, nil, nil))
+	is.True(conditions["isNumeric"](" 1.2 ", nil, nil))
+	is.True(conditions["isNumeric"](uint8(1), nil, nil))
+	is.False(conditions["isNumeric"]("abc", nil, nil))
+	is.True(conditions["isSlice"]([]int{1}, nil, nil))
+	is.True(conditions["isMap"](map[string]int{}, nil, nil))
+	is.True(conditions["eq"](23, []string{"23"}, nil))
+	is.False(conditions["eq"](23, nil, nil))
+	is.False(conditions["fieldEq"](23, []string{"type", "a"}, nil))
+
+	AddCondition("isAdmin", func(val any, args []string, f *Filtration) bool {
+		return f != nil && f.String("role") == "admin"
+	})
+	is.NoErr(checkCond("isAdmin"))
+	is.True(errors.Is(checkCond("not-exist"), ErrUnknownFilter))
+	is.Panics(func() {
+		AddCondition("", nil)
+	})
+}
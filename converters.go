@@ -1,7 +1,10 @@
 package filter
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 	"text/template"
@@ -22,8 +25,10 @@ var (
 	EscapeJS = template.JSEscapeString
 	// EscapeHTML escape html string
 	EscapeHTML = template.HTMLEscapeString
-	// error for params
-	errInvalidParam = errors.New("invalid input parameter")
+	// error for convert type
+	errConvType = errors.New("unsupported convert type")
+	// error for the number is out of range of the convert type
+	errOverflow = errors.New("value out of range")
 )
 
 /*************************************************************
@@ -173,3 +178,188 @@ func StringsToInts(ss []string) ([]int, error) { return arrutil.StringsToInts(ss
 func StrToTime(s string, layouts ...string) (time.Time, error) {
 	return strutil.ToTime(s, layouts...)
 }
+
+/*************************************************************
+ * convert value to reflect type
+ *************************************************************/
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// convType convert the value to the given reflect type.
+// use the ToInt, ToBool, ToFloat, StrToTime... for converting.
+func convType(val any, typ reflect.Type) (rv reflect.Value, err error) {
+	if val == nil {
+		return reflect.Zero(typ), nil
+	}
+
+	// up: support pointer string value
+	if ps, ok := val.(*string); ok {
+		val = *ps
+	}
+
+	rv = reflect.ValueOf(val)
+	if rv.Type().AssignableTo(typ) {
+		return rv, nil
+	}
+
+	str, isStr := val.(string)
+	switch {
+	case typ == timeType:
+		if isStr {
+			t, err := StrToTime(str)
+			return reflect.ValueOf(t), err
+		}
+	case typ == durationType:
+		if isStr {
+			d, err := strutil.ToDuration(str)
+			return reflect.ValueOf(d), err
+		}
+
+		i64, err := ToInt64(val)
+		return reflect.ValueOf(time.Duration(i64)), err
+	}
+
+	var newVal any
+	switch typ.Kind() {
+	case reflect.String:
+		newVal, err = ToString(val)
+	case reflect.Bool:
+		if isStr {
+			newVal, err = ToBool(str)
+		} else {
+			err = errConvType
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		newVal, err = ToInt64(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		newVal, err = ToUint64(val)
+	case reflect.Float32, reflect.Float64:
+		if isStr {
+			newVal, err = ToFloat(str)
+		} else {
+			newVal, err = mathutil.ToFloat(val)
+		}
+	case reflect.Ptr:
+		var elem reflect.Value
+		if elem, err = convType(val, typ.Elem()); err == nil {
+			rv = reflect.New(typ.Elem())
+			rv.Elem().Set(elem)
+			return rv, nil
+		}
+	case reflect.Slice:
+		if isStr {
+			val = StrToSlice(str)
+		}
+		return convSlice(val, typ)
+	case reflect.Map:
+		return convMap(val, typ)
+	default:
+		if rv.Type().ConvertibleTo(typ) {
+			return rv.Convert(typ), nil
+		}
+		err = errConvType
+	}
+
+	if err != nil {
+		return rv, fmt.Errorf("cannot convert %T to %s: %w", val, typ, err)
+	}
+
+	nv := reflect.ValueOf(newVal)
+	if isOverflow(nv, typ) {
+		return rv, fmt.Errorf("cannot convert %T to %s: %w", val, typ, errOverflow)
+	}
+	return nv.Convert(typ), nil
+}
+
+// isOverflow check the number value cannot be represented by the number type.
+func isOverflow(nv reflect.Value, typ reflect.Type) bool {
+	zero := reflect.Zero(typ)
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return zero.OverflowInt(nv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return zero.OverflowUint(nv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return zero.OverflowFloat(nv.Float())
+	}
+	return false
+}
+
+// convMap convert a map value to the given map type
+func convMap(val any, typ reflect.Type) (rv reflect.Value, err error) {
+	srcRv := reflect.ValueOf(val)
+	if srcRv.Kind() != reflect.Map {
+		return rv, fmt.Errorf("cannot convert %T to %s: %w", val, typ, errConvType)
+	}
+
+	rv = reflect.MakeMapWithSize(typ, srcRv.Len())
+	iter := srcRv.MapRange()
+	for iter.Next() {
+		key, err := convType(iter.Key().Interface(), typ.Key())
+		if err != nil {
+			return rv, err
+		}
+
+		elem, err := convType(iter.Value().Interface(), typ.Elem())
+		if err != nil {
+			return rv, err
+		}
+		rv.SetMapIndex(key, elem)
+	}
+	return rv, nil
+}
+
+// convSlice convert a slice value to the given slice type
+func convSlice(val any, typ reflect.Type) (rv reflect.Value, err error) {
+	srcRv := reflect.ValueOf(val)
+	if srcRv.Kind() != reflect.Slice && srcRv.Kind() != reflect.Array {
+		return rv, fmt.Errorf("cannot convert %T to %s: %w", val, typ, errConvType)
+	}
+
+	rv = reflect.MakeSlice(typ, srcRv.Len(), srcRv.Len())
+	for i := 0; i < srcRv.Len(); i++ {
+		elem, err := convType(srcRv.Index(i).Interface(), typ.Elem())
+		if err != nil {
+			return rv, err
+		}
+		rv.Index(i).Set(elem)
+	}
+	return rv, nil
+}
+
+// jsonNumber convert the json.Number in the value decoded by UseNumber() to the
+// int, uint64 or float64. the integer that out of range of uint64 will be kept
+// as json.Number, so it can be encoded exactly.
+func jsonNumber(val any) any {
+	switch tv := val.(type) {
+	case json.Number:
+		if iv, err := tv.Int64(); err == nil {
+			return int(iv)
+		}
+
+		str := tv.String()
+		if !strings.ContainsAny(str, ".eE") {
+			if uv, err := strconv.ParseUint(str, 10, 64); err == nil {
+				return uv
+			}
+			return tv
+		}
+
+		if fv, err := tv.Float64(); err == nil {
+			return fv
+		}
+		return tv
+	case []any:
+		for i, v := range tv {
+			tv[i] = jsonNumber(v)
+		}
+	case map[string]any:
+		for k, v := range tv {
+			tv[k] = jsonNumber(v)
+		}
+	}
+	return val
+}
@@ -0,0 +1,160 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors, can be checked by errors.Is()
+var (
+	// ErrUnknownFilter the filter name is not exists
+	ErrUnknownFilter = errors.New("unknown filter")
+	// ErrTypeMismatch the value type is not match the filter
+	ErrTypeMismatch = errors.New("value type mismatch")
+	// ErrBadArgs the filter args is invalid
+	ErrBadArgs = errors.New("invalid filter args")
+	// ErrRuleCycle the rule dependencies has cycle. see Rule.After()
+	ErrRuleCycle = errors.New("rule dependencies has cycle")
+	// ErrKeyNotFound the key is not exists in the filtered data. see GetAs()
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrRequired the required field is missing or empty. see Rule.Required()
+	ErrRequired = errors.New("value is required")
+	// ErrBodyTooLarge the request body size is over the MaxBodySize
+	ErrBodyTooLarge = errors.New("request body too large")
+)
+
+var errEmptyFields = errors.New("filter: invalid fields parameters, cannot be empty")
+
+// FilterError is the error of apply filter(s) to a field value.
+//
+// Usage:
+//
+//	var fe *filter.FilterError
+//	if errors.As(err, &fe) {
+//		fmt.Println(fe.Field, fe.Filter, fe.Value)
+//	}
+//
+// In the collect errors mode, errors.As() will get the first *FilterError
+// of the Errors. use errors.As(err, &es) with filter.Errors to get all.
+type FilterError struct {
+	// Field name or path of the value. empty on call Apply() directly.
+	Field string
+	// Index of the rule in the Filtration
+	Index int
+	// Filter name. "func" for the custom rule func
+	Filter string
+	// Args for the filter
+	Args []string
+	// Value input value of the filter
+	Value any
+	// Err the underlying error
+	Err error
+}
+
+func newFilterError(name string, val any, args []string, err error) *FilterError {
+	if fe, ok := err.(*FilterError); ok {
+		return fe
+	}
+	return &FilterError{Filter: name, Args: args, Value: val, Err: err}
+}
+
+// Error string
+func (e *FilterError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("filter: apply '%s' error: %v", e.Filter, e.Err)
+	}
+	return fmt.Sprintf("filter: field '%s' apply '%s' error: %v", e.Field, e.Filter, e.Err)
+}
+
+// Unwrap get the underlying error
+func (e *FilterError) Unwrap() error {
+	return e.Err
+}
+
+// Errors collected filter errors on filtering.
+type Errors []*FilterError
+
+// Error string, implements the error interface
+func (es Errors) Error() string {
+	var sb strings.Builder
+	for i, e := range es {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(e.Error())
+	}
+	return sb.String()
+}
+
+// Is check any error is matched the target, for use errors.Is()
+func (es Errors) Is(target error) bool {
+	for _, e := range es {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As find the first error that matches the target, for use errors.As().
+// eg: get the first *FilterError in the collect errors mode.
+func (es Errors) As(target any) bool {
+	for _, e := range es {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Empty check
+func (es Errors) Empty() bool {
+	return len(es) == 0
+}
+
+// HasField check the field has error
+func (es Errors) HasField(field string) bool {
+	for _, e := range es {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Field get the errors of the field
+func (es Errors) Field(field string) (fes []*FilterError) {
+	for _, e := range es {
+		if e.Field == field {
+			fes = append(fes, e)
+		}
+	}
+	return
+}
+
+// One get the first error message
+func (es Errors) One() string {
+	if len(es) > 0 {
+		return es[0].Error()
+	}
+	return ""
+}
+
+// All get all error messages, grouped by field.
+// the message is the underlying error message.
+func (es Errors) All() map[string][]string {
+	mp := make(map[string][]string, len(es))
+	for _, e := range es {
+		mp[e.Field] = append(mp[e.Field], e.Err.Error())
+	}
+	return mp
+}
+
+// MarshalJSON marshal errors to JSON, output is the All() result.
+//
+// eg: {"age": ["strconv.Atoi: parsing \"abc\": invalid syntax"]}
+func (es Errors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(es.All())
+}
@@ -0,0 +1,71 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestErrors(t *testing.T) {
+	is := assert.New(t)
+
+	es := Errors{}
+	is.True(es.Empty())
+	is.Eq("", es.One())
+
+	es = append(es,
+		&FilterError{Field: "age", Filter: "int", Value: "abc", Err: errors.New("invalid int")},
+		&FilterError{Field: "name", Filter: "upper", Value: 23, Err: errors.New("invalid string")},
+		&FilterError{Field: "age", Filter: "min", Args: []string{"1"}, Value: 0, Err: errors.New("too small")},
+	)
+
+	is.False(es.Empty())
+	is.True(es.HasField("age"))
+	is.False(es.HasField("not-exist"))
+	is.Len(es.Field("age"), 2)
+	is.Eq("filter: field 'age' apply 'int' error: invalid int", es.One())
+	is.Eq("filter: field 'age' apply 'int' error: invalid int; filter: field 'name' apply 'upper' error: invalid string; filter: field 'age' apply 'min' error: too small", es.Error())
+	is.Eq(map[string][]string{
+		"age":  {"invalid int", "too small"},
+		"name": {"invalid string"},
+	}, es.All())
+
+	bs, err := es.MarshalJSON()
+	is.NoErr(err)
+	is.Eq(`{"age":["invalid int","too small"],"name":["invalid string"]}`, string(bs))
+
+	var fe *FilterError
+	is.True(errors.As(es[0], &fe))
+	is.Eq("int", fe.Filter)
+	is.Eq("invalid int", errors.Unwrap(fe).Error())
+
+	fe = newFilterError("substr", "abc", []string{"1"}, ErrBadArgs)
+	is.Eq("filter: apply 'substr' error: invalid filter args", fe.Error())
+	is.True(errors.Is(fe, ErrBadArgs))
+	is.Eq(fe, newFilterError("other", "abc", nil, fe))
+
+	// Errors.Is
+	es = append(es, fe)
+	is.True(errors.Is(es, ErrBadArgs))
+	is.False(errors.Is(es, ErrTypeMismatch))
+
+	// Errors.As
+	fe = nil
+	is.True(errors.As(es, &fe))
+	is.Eq("age", fe.Field)
+	is.Eq("int", fe.Filter)
+	var err2 Errors
+	is.True(errors.As(error(es), &err2))
+	is.Len(err2, 4)
+	var pe *ParseError
+	is.False(errors.As(es, &pe))
+
+	// in the collect errors mode
+	f := New(map[string]any{"age": "abc", "name": " tom "}).WithOptions(CollectErrors)
+	f.AddRule("name", "trim")
+	f.AddRule("age", "int")
+	err = f.Filtering()
+	is.True(errors.As(err, &fe))
+	is.Eq("age", fe.Field)
+}
@@ -8,6 +8,7 @@
 package filter
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gookit/goutil/arrutil"
@@ -16,11 +17,204 @@ import (
 	"github.com/gookit/goutil/strutil"
 )
 
+// FilterFunc definition.
+//
+// val is the value to be filtered, args are the parsed filter args.
+// eg: rule "substr:0,2" will call fn(val, []string{"0", "2"})
+type FilterFunc func(val any, args []string) (any, error)
+
+// ContextFilterFunc definition. the context-aware filter func.
+//
+// ctx is the context passed to Filtration.FilteringContext(), it can be used
+// for cancellation and read the request-scoped values.
+type ContextFilterFunc func(ctx context.Context, val any, args []string) (any, error)
+
+// custom filters. key is filter name.
+var customFilters = make(map[string]ContextFilterFunc)
+
+// built-in filters with checked args
+var substrFilter = newFuncFilter("substr", Substr)
+
+// argCheckers check the filter args before apply. eg: on load schema.
+// the filters not in the map will not be checked.
+var argCheckers = map[string]func(args []string) error{
+	"substr":   substrFilter.CheckArgs,
+	"default":  argNumChecker(1, 1),
+	"uniqueBy": argNumChecker(1, 1),
+}
+
+func init() {
+	// the built-in filters without args
+	for _, name := range []string{
+		"int", "uint", "int64", "float", "unique", "uniqueFold", "trimStrings", "stringsToInts",
+		"bool", "title", "email", "lower", "upper", "lowerFirst", "upperFirst", "upperWord",
+		"URLEncode", "URLDecode", "escapeJS", "escapeHTML",
+	} {
+		argCheckers[name] = argNumChecker(0, 0)
+	}
+
+	// the built-in filters with an optional separator arg
+	for _, name := range []string{"snakeCase", "camelCase", "strToInts", "strToSlice"} {
+		argCheckers[name] = argNumChecker(0, 1)
+	}
+}
+
+// argNumChecker create a checker for the args number, max < 0 is no limit.
+func argNumChecker(min, max int) func(args []string) error {
+	return func(args []string) error {
+		n := len(args)
+		if n >= min && (max < 0 || n <= max) {
+			return nil
+		}
+
+		if min == max {
+			return fmt.Errorf("%w: expect %d args, but given %d", ErrBadArgs, min, n)
+		}
+		return fmt.Errorf("%w: expect %d to %d args, but given %d", ErrBadArgs, min, max, n)
+	}
+}
+
+// checkArgs check the args of the filter by name. see argCheckers
+func checkArgs(name string, args []string) error {
+	if fn, ok := argCheckers[Name(name)]; ok {
+		return fn(args)
+	}
+	return nil
+}
+
+// AddFilter add a custom filter func to global.
+//
+// The fn can be a FilterFunc, ContextFilterFunc, or any typed func. for typed
+// func, the first param is the filtering value, other params are the filter
+// args, they will be converted to the declared param types. see funcFilter
+//
+// Usage:
+//
+//	filter.AddFilter("normalizeSku", func(val any, args []string) (any, error) {
+//		return strings.ReplaceAll(val.(string), "-", ""), nil
+//	})
+//	filter.AddFilter("repeat", func(s string, n int) string {
+//		return strings.Repeat(s, n)
+//	})
+//	filter.AddFilter("synonym", func(ctx context.Context, val any, args []string) (any, error) {
+//		return lookupSynonym(ctx, val.(string))
+//	})
+//	f.AddRule("sku", "trim|normalizeSku|upper|repeat:2")
+func AddFilter(name string, fn any) {
+	if name == "" || fn == nil {
+		panic("filter: the filter name and func cannot be empty")
+	}
+	cfn, ff := newFilter(name, fn)
+	customFilters[name] = cfn
+
+	// the typed func can check args by the param types
+	if ff != nil {
+		argCheckers[name] = ff.CheckArgs
+	} else {
+		delete(argCheckers, name)
+	}
+}
+
+// AddFilters add multi custom filter func to global.
+func AddFilters(fns map[string]any) {
+	for name, fn := range fns {
+		AddFilter(name, fn)
+	}
+}
+
+// AddAlias add an alias name for a built-in or custom filter.
+func AddAlias(alias, name string) {
+	filterAliases[alias] = name
+}
+
+// HasFilter check the filter is exists. contains built-in and custom filters.
+func HasFilter(name string) bool {
+	if _, ok := customFilters[Name(name)]; ok {
+		return true
+	}
+	return IsBuiltIn(name)
+}
+
+// resolveFilter find the filter func by name. returns nil on not found.
+func resolveFilter(name string) ContextFilterFunc {
+	realName := Name(name)
+	if fn, ok := customFilters[realName]; ok {
+		return fn
+	}
+
+	if IsBuiltIn(realName) {
+		return func(ctx context.Context, val any, args []string) (any, error) {
+			return applyFilter(ctx, realName, val, args)
+		}
+	}
+	return nil
+}
+
+// checkFilter check the filter name is exists and the filter args, the names are
+// extra filter names. will skip the name check on lenient is true.
+func checkFilter(name string, args []string, names map[string]ContextFilterFunc, lenient bool) error {
+	if !lenient {
+		if err := checkName(name, names); err != nil {
+			return err
+		}
+	}
+
+	if err := checkArgs(name, args); err != nil {
+		return fmt.Errorf("check '%s' error: %w", name, err)
+	}
+	return nil
+}
+
+// checkName check the filter name is exists. the names are extra filter names.
+func checkName(name string, names map[string]ContextFilterFunc) error {
+	if _, ok := names[Name(name)]; ok || HasFilter(name) {
+		return nil
+	}
+
+	all := builtInNames()
+	for n := range customFilters {
+		all = append(all, n)
+	}
+	for n := range names {
+		all = append(all, n)
+	}
+
+	if suggest := suggestName(name, all); suggest != "" {
+		return fmt.Errorf("%w '%s', did you mean '%s'?", ErrUnknownFilter, name, suggest)
+	}
+	return fmt.Errorf("%w '%s'", ErrUnknownFilter, name)
+}
+
 // Apply a filter by name. for filter value.
+//
+// Will find the custom filters first, then the built-in filters.
+// The returned error is a *FilterError, can use errors.Is() to check
+// the sentinel errors. eg: ErrTypeMismatch
 func Apply(name string, val any, args []string) (any, error) {
+	return ApplyContext(context.Background(), name, val, args)
+}
+
+// ApplyContext apply a filter by name with context. see Apply()
+func ApplyContext(ctx context.Context, name string, val any, args []string) (any, error) {
+	newVal, err := applyFilter(ctx, name, val, args)
+	if err != nil {
+		return nil, newFilterError(name, val, args, err)
+	}
+	return newVal, nil
+}
+
+func applyFilter(ctx context.Context, name string, val any, args []string) (any, error) {
 	var err error
 	realName := Name(name)
 
+	// custom filter
+	if fn, ok := customFilters[realName]; ok {
+		return fn(ctx, val, args)
+	}
+	if !IsBuiltIn(realName) {
+		return nil, ErrUnknownFilter
+	}
+
 	// don't limit value type
 	if _, ok := dontLimitType[realName]; ok {
 		switch realName {
@@ -34,17 +228,31 @@ func Apply(name string, val any, args []string) (any, error) {
 			val, err = mathutil.ToFloat(val)
 		case "unique":
 			val = Unique(val)
+		case "uniqueBy":
+			if len(args) == 0 {
+				err = fmt.Errorf("%w: expect 1 args, but given 0", ErrBadArgs)
+			} else {
+				val = UniqueBy(val, args[0])
+			}
+		case "uniqueFold":
+			val = UniqueFold(val)
+		case "default":
+			if len(args) == 0 {
+				err = fmt.Errorf("%w: expect 1 args, but given 0", ErrBadArgs)
+			} else if isEmpty(val) {
+				val = args[0]
+			}
 		case "trimStrings":
 			if ss, ok := val.([]string); ok {
 				val = arrutil.TrimStrings(ss)
 			} else {
-				err = errInvalidParam
+				err = fmt.Errorf("%w: only use for []string type, input %T", ErrTypeMismatch, val)
 			}
 		case "stringsToInts":
 			if ss, ok := val.([]string); ok {
 				val, err = arrutil.StringsToInts(ss)
 			} else {
-				err = errInvalidParam
+				err = fmt.Errorf("%w: only use for []string type, input %T", ErrTypeMismatch, val)
 			}
 		}
 		return val, err
@@ -57,11 +265,74 @@ func Apply(name string, val any, args []string) (any, error) {
 	if poStr, ok := val.(*string); ok {
 		str = *poStr
 	} else if str, ok = val.(string); !ok {
-		return nil, fmt.Errorf("filter: '%s' only use for string type, input %T", name, val)
+		// apply to each element of the slice or map value
+		if _, ok := convFilters[realName]; !ok {
+			return applyEach(ctx, realName, val, args)
+		}
+		return nil, fmt.Errorf("%w: only use for string type, input %T", ErrTypeMismatch, val)
+	}
+
+	return applyStrFilter(ctx, realName, str, args)
+}
+
+// apply the string filter to each element of the []string, []any,
+// map[string]string and map[string]any value. the element must be string.
+func applyEach(ctx context.Context, name string, val any, args []string) (any, error) {
+	apply := func(elem any, key any) (string, error) {
+		str, ok := elem.(string)
+		if !ok {
+			return "", fmt.Errorf("%w: only use for string type, input %T of the element %v", ErrTypeMismatch, elem, key)
+		}
+
+		newVal, err := applyStrFilter(ctx, name, str, args)
+		if err != nil {
+			return "", err
+		}
+		return newVal.(string), nil
+	}
+
+	var err error
+	switch tv := val.(type) {
+	case []string:
+		ss := make([]string, len(tv))
+		for i, s := range tv {
+			if ss[i], err = apply(s, i); err != nil {
+				return nil, err
+			}
+		}
+		return ss, nil
+	case []any:
+		ls := make([]any, len(tv))
+		for i, elem := range tv {
+			if ls[i], err = apply(elem, i); err != nil {
+				return nil, err
+			}
+		}
+		return ls, nil
+	case map[string]string:
+		mp := make(map[string]string, len(tv))
+		for k, s := range tv {
+			if mp[k], err = apply(s, k); err != nil {
+				return nil, err
+			}
+		}
+		return mp, nil
+	case map[string]any:
+		mp := make(map[string]any, len(tv))
+		for k, elem := range tv {
+			if mp[k], err = apply(elem, k); err != nil {
+				return nil, err
+			}
+		}
+		return mp, nil
 	}
 
-	// val is must be string.
-	switch realName {
+	return nil, fmt.Errorf("%w: only use for string type, input %T", ErrTypeMismatch, val)
+}
+
+// apply the string filter. name must be in the stringFilters
+func applyStrFilter(ctx context.Context, name, str string, args []string) (val any, err error) {
+	switch name {
 	case "bool":
 		val, err = strutil.ToBool(str)
 	case "trim":
@@ -75,7 +346,7 @@ func Apply(name string, val any, args []string) (any, error) {
 	case "email":
 		val = strutil.FilterEmail(str)
 	case "substr":
-		val = strutil.Substr(str, MustInt(args[0]), MustInt(args[1]))
+		val, err = substrFilter.Call(ctx, str, args)
 	case "lower":
 		val = strutil.Lowercase(str)
 	case "upper":
@@ -113,14 +384,3 @@ func Apply(name string, val any, args []string) (any, error) {
 func GetByPath(key string, mp map[string]any) (any, bool) {
 	return maputil.GetByPath(key, mp)
 }
-
-func parseArgString(argStr string) (ss []string) {
-	if argStr == "" { // no arg
-		return
-	}
-
-	if len(argStr) == 1 { // one char
-		return []string{argStr}
-	}
-	return strutil.Split(argStr, ",")
-}
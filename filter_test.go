@@ -1,6 +1,8 @@
 package filter_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/gookit/filter"
@@ -18,4 +20,116 @@ func TestApply(t *testing.T) {
 	ret, err = filter.Apply("trim", ps, nil)
 	assert.NoErr(t, err)
 	assert.Equal(t, "abc", ret)
+
+	// substr with checked args
+	ret, err = filter.Apply("substr", "abcDEF", []string{"3", "2"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "DE", ret)
+	_, err = filter.Apply("substr", "abcDEF", []string{"3"})
+	assert.ErrMsg(t, err, "filter: apply 'substr' error: invalid filter args: expect 2 args, but given 1")
+	assert.True(t, errors.Is(err, filter.ErrBadArgs))
+	_, err = filter.Apply("substr", "abcDEF", []string{"a", "2"})
+	assert.True(t, errors.Is(err, filter.ErrBadArgs))
+
+	// type mismatch
+	_, err = filter.Apply("lower", 23, nil)
+	assert.True(t, errors.Is(err, filter.ErrTypeMismatch))
+	var fe *filter.FilterError
+	assert.True(t, errors.As(err, &fe))
+	assert.Eq(t, "lower", fe.Filter)
+	assert.Eq(t, 23, fe.Value)
+
+	// unknown filter
+	_, err = filter.Apply("lowr", "abc", nil)
+	assert.True(t, errors.Is(err, filter.ErrUnknownFilter))
+	_, err = filter.Apply("lowr", 23, nil)
+	assert.True(t, errors.Is(err, filter.ErrUnknownFilter))
+}
+
+func TestApply_each(t *testing.T) {
+	is := assert.New(t)
+
+	ret, err := filter.Apply("lower", []string{"Go", "PHP"}, nil)
+	is.NoErr(err)
+	is.Eq([]string{"go", "php"}, ret)
+
+	src := []any{" a ", " b"}
+	ret, err = filter.Apply("trim", src, nil)
+	is.NoErr(err)
+	is.Eq([]any{"a", "b"}, ret)
+	is.Eq(" a ", src[0]) // not change the input
+
+	ret, err = filter.Apply("snake", map[string]string{"h1": "ContentType"}, nil)
+	is.NoErr(err)
+	is.Eq(map[string]string{"h1": "content_type"}, ret)
+
+	ret, err = filter.Apply("substr", map[string]any{"k": "abcdef"}, []string{"1", "2"})
+	is.NoErr(err)
+	is.Eq(map[string]any{"k": "bc"}, ret)
+
+	// element is not string
+	_, err = filter.Apply("upper", []any{"a", 2}, nil)
+	is.True(errors.Is(err, filter.ErrTypeMismatch))
+	is.StrContains(err.Error(), "input int of the element 1")
+
+	// convert filters are not applied to each element
+	_, err = filter.Apply("bool", []string{"true"}, nil)
+	is.True(errors.Is(err, filter.ErrTypeMismatch))
+	_, err = filter.Apply("lower", []int{1}, nil)
+	is.True(errors.Is(err, filter.ErrTypeMismatch))
+
+	// in rule
+	f := filter.New(map[string]any{
+		"tags":    []string{" Go ", "PHP "},
+		"headers": map[string]string{"Accept": " Text/HTML "},
+	})
+	f.AddRule("tags,headers", "trim|lower")
+	is.NoErr(f.Filtering())
+	is.Eq([]string{"go", "php"}, f.SafeVal("tags"))
+	is.Eq(map[string]string{"Accept": "text/html"}, f.SafeVal("headers"))
+}
+
+func TestHasFilter(t *testing.T) {
+	assert.True(t, filter.HasFilter("trim"))
+	assert.True(t, filter.HasFilter("int"))
+	assert.True(t, filter.HasFilter("ucFirst"))
+	assert.False(t, filter.HasFilter("not-exist"))
+
+	assert.True(t, filter.IsBuiltIn("str2arr"))
+	assert.False(t, filter.IsBuiltIn("not-exist"))
+}
+
+func TestAddFilter(t *testing.T) {
+	filter.AddFilter("normalizeSku", func(val any, args []string) (any, error) {
+		return strings.ReplaceAll(val.(string), "-", ""), nil
+	})
+	filter.AddFilters(map[string]any{
+		"repeat": func(s string, n int) string {
+			return strings.Repeat(s, n)
+		},
+	})
+	filter.AddAlias("normSku", "normalizeSku")
+
+	ret, err := filter.Apply("normalizeSku", "ab-12", nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, "ab12", ret)
+
+	ret, err = filter.Apply("normSku", "ab-12", nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, "ab12", ret)
+
+	ret, err = filter.Apply("repeat", "ab", []string{"2"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "abab", ret)
+
+	f := filter.New(map[string]any{"sku": " ab-12 ", "code": "x"})
+	f.AddRule("sku", "trim|normSku|upper")
+	f.AddRule("code", "repeat:3")
+	assert.NoErr(t, f.Filtering())
+	assert.Eq(t, "AB12", f.String("sku"))
+	assert.Eq(t, "xxx", f.String("code"))
+
+	assert.Panics(t, func() {
+		filter.AddFilter("", nil)
+	})
 }
@@ -2,6 +2,7 @@ package filter
 
 import (
 	"net/url"
+	"reflect"
 	"strings"
 
 	"github.com/gookit/goutil/arrutil"
@@ -14,11 +15,51 @@ var dontLimitType = map[string]uint8{
 	"int64":  1,
 	"float":  1,
 	"unique": 1,
+	// unique by the map key or struct field. eg: "uniqueBy:id"
+	"uniqueBy": 1,
+	// unique strings case-insensitively
+	"uniqueFold": 1,
+	// use the arg on value is empty. eg: "default:guest"
+	"default": 1,
 	// list
 	"trimStrings":   1,
 	"stringsToInts": 1,
 }
 
+// built-in filters, only use for string value.
+var stringFilters = map[string]uint8{
+	"bool":       1,
+	"trim":       1,
+	"trimLeft":   1,
+	"trimRight":  1,
+	"title":      1,
+	"email":      1,
+	"substr":     1,
+	"lower":      1,
+	"upper":      1,
+	"lowerFirst": 1,
+	"upperFirst": 1,
+	"upperWord":  1,
+	"snakeCase":  1,
+	"camelCase":  1,
+	"URLEncode":  1,
+	"URLDecode":  1,
+	"escapeJS":   1,
+	"escapeHTML": 1,
+	"strToInts":  1,
+	"strToSlice": 1,
+	"strToTime":  1,
+}
+
+// string filters that convert the value to other type.
+// other string filters can apply to each element of the slice and map value.
+var convFilters = map[string]uint8{
+	"bool":       1,
+	"strToInts":  1,
+	"strToSlice": 1,
+	"strToTime":  1,
+}
+
 var filterAliases = map[string]string{
 	"toInt":   "int",
 	"toUint":  "uint",
@@ -71,6 +112,45 @@ func Name(name string) string {
 	return name
 }
 
+// IsBuiltIn check the name is a built-in filter. will resolve alias name.
+func IsBuiltIn(name string) bool {
+	realName := Name(name)
+	if _, ok := dontLimitType[realName]; ok {
+		return true
+	}
+
+	_, ok := stringFilters[realName]
+	return ok
+}
+
+// builtInNames get all built-in filter names and aliases
+func builtInNames() []string {
+	names := make([]string, 0, len(dontLimitType)+len(stringFilters)+len(filterAliases))
+	for name := range dontLimitType {
+		names = append(names, name)
+	}
+	for name := range stringFilters {
+		names = append(names, name)
+	}
+	for alias := range filterAliases {
+		names = append(names, alias)
+	}
+	return names
+}
+
+// suggestName find the most similar name from the names. return empty if not found.
+func suggestName(name string, names []string) (suggest string) {
+	var minRate float32 = 0.3
+	for _, n := range names {
+		rate, _ := strutil.Similarity(name, n, 0)
+		if rate < minRate || rate == minRate && (suggest == "" || n < suggest) {
+			minRate = rate
+			suggest = n
+		}
+	}
+	return
+}
+
 /*************************************************************
  * built in filters
  *************************************************************/
@@ -124,60 +204,145 @@ func URLDecode(s string) string {
 	return s
 }
 
-// Unique value in the given array, slice.
+// UniqueOf remove the duplicate values of the slice, keep the first occurrence order.
+func UniqueOf[T comparable](ls []T) []T {
+	mp := make(map[T]struct{}, len(ls))
+	ns := make([]T, 0, len(ls))
+	for _, v := range ls {
+		if _, ok := mp[v]; !ok {
+			mp[v] = struct{}{}
+			ns = append(ns, v)
+		}
+	}
+	return ns
+}
+
+// Unique value in the given array, slice. keep the first occurrence order.
+//
+// Support slice of any comparable element type, the not comparable
+// elements(eg: map, slice in []any) will be kept.
 func Unique(val any) any {
 	switch tv := val.(type) {
 	case []int:
-		mp := make(map[int]int)
-		for _, sVal := range tv {
-			mp[sVal] = 1
-		}
+		return UniqueOf(tv)
+	case []int64:
+		return UniqueOf(tv)
+	case []string:
+		return UniqueOf(tv)
+	}
 
-		// no repeat value
-		if len(tv) == len(mp) {
-			return tv
+	return uniqueByKey(val, func(elem reflect.Value) (any, bool) {
+		if isHashable(elem) {
+			return valueOf(elem), true
 		}
+		return nil, false
+	})
+}
 
-		var ns []int
-		for sVal := range mp {
-			ns = append(ns, sVal)
+// UniqueBy remove the duplicate elements by the key value, keep the first occurrence order.
+// the element should be a map or struct, key is the map key or struct field name.
+//
+// The elements without the key will be kept.
+func UniqueBy(val any, key string) any {
+	return uniqueByKey(val, func(elem reflect.Value) (any, bool) {
+		if elem.Kind() == reflect.Ptr && !elem.IsNil() {
+			elem = elem.Elem()
 		}
-		return ns
-	case []int64:
-		mp := make(map[int64]int)
-		for _, sVal := range tv {
-			mp[sVal] = 1
+
+		var kv reflect.Value
+		switch elem.Kind() {
+		case reflect.Map:
+			if elem.Type().Key().Kind() == reflect.String {
+				kv = elem.MapIndex(reflect.ValueOf(key).Convert(elem.Type().Key()))
+			}
+		case reflect.Struct:
+			kv = elem.FieldByName(key)
 		}
 
-		// no repeat value
-		if len(tv) == len(mp) {
-			return tv
+		if kv.IsValid() && kv.Kind() == reflect.Interface {
+			kv = kv.Elem()
+		}
+		if !kv.IsValid() || !kv.CanInterface() || !isHashable(kv) {
+			return nil, false
 		}
+		return kv.Interface(), true
+	})
+}
 
-		var ns []int64
-		for sVal := range mp {
-			ns = append(ns, sVal)
+// UniqueFold remove the duplicate strings case-insensitively, keep the first occurrence.
+// support []string and []any, other elements will be compared as the Unique().
+func UniqueFold(val any) any {
+	return uniqueByKey(val, func(elem reflect.Value) (any, bool) {
+		if elem.Kind() == reflect.String {
+			return strings.ToLower(elem.String()), true
 		}
-		return ns
-	case []string:
-		mp := make(map[string]int)
-		for _, sVal := range tv {
-			mp[sVal] = 1
+		if isHashable(elem) {
+			return valueOf(elem), true
+		}
+		return nil, false
+	})
+}
+
+// uniqueByKey remove the duplicate elements by the key func. the elements
+// that keyFn returns false will be kept. will return val on it is not slice.
+func uniqueByKey(val any, keyFn func(elem reflect.Value) (any, bool)) any {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return val
+	}
+
+	mp := make(map[any]struct{}, rv.Len())
+	ns := reflect.MakeSlice(reflect.SliceOf(rv.Type().Elem()), 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		// get the element value in the interface
+		ev := elem
+		if ev.Kind() == reflect.Interface {
+			ev = ev.Elem()
 		}
 
-		// no repeat value
-		if len(tv) == len(mp) {
-			return tv
+		if key, ok := keyFn(ev); ok {
+			if _, has := mp[key]; has {
+				continue
+			}
+			mp[key] = struct{}{}
 		}
+		ns = reflect.Append(ns, elem)
+	}
+	return ns.Interface()
+}
 
-		var ns []string
-		for sVal := range mp {
-			ns = append(ns, sVal)
+// isHashable check the value can be used as the map key. it checks the dynamic
+// values in the interface, eg: struct{ V any } with a slice value is not hashable.
+func isHashable(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Map, reflect.Slice, reflect.Func:
+		return false
+	case reflect.Interface:
+		return rv.IsNil() || isHashable(rv.Elem())
+	case reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if !isHashable(rv.Index(i)) {
+				return false
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			if !isHashable(rv.Field(i)) {
+				return false
+			}
 		}
-		return ns
 	}
+	return true
+}
 
-	return val
+func valueOf(rv reflect.Value) any {
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
 }
 
 // Substr cut string
@@ -65,6 +65,9 @@ func TestURLEnDecode(t *testing.T) {
 	is.Eq("a.com", URLDecode("a.com"))
 }
 
+// anyBox is comparable, but not hashable on the V is a slice
+type anyBox struct{ V any }
+
 func TestUnique(t *testing.T) {
 	is := assert.New(t)
 
@@ -75,4 +78,78 @@ func TestUnique(t *testing.T) {
 	is.Len(Unique([]string{"a", "b"}), 2)
 	is.Len(Unique([]string{"a", "b", "b"}), 2)
 	is.Eq("invalid", Unique("invalid"))
+
+	// keep the first occurrence order
+	is.Eq([]int{1, 2}, Unique([]int{1, 2, 2, 1}))
+	is.Eq([]int64{3, 1, 2}, Unique([]int64{3, 1, 3, 2, 1}))
+	is.Eq([]string{"b", "a"}, Unique([]string{"b", "a", "b"}))
+	is.Eq([]float64{1.5, 2}, Unique([]float64{1.5, 2, 1.5}))
+	is.Eq([]uint{2, 1}, Unique([]uint{2, 1, 2}))
+	is.Eq([]any{1, "1", nil}, Unique([]any{1, "1", 1, nil, nil}))
+	is.Eq([]int{1, 2}, Unique([3]int{1, 2, 1}))
+
+	type level string
+	is.Eq([]level{"high", "low"}, Unique([]level{"high", "low", "high"}))
+
+	// not comparable elements are kept
+	is.Eq([]any{[]int{1}, []int{1}, 2}, Unique([]any{[]int{1}, []int{1}, 2, 2}))
+
+	// the comparable type with not hashable dynamic value
+	is.Eq([]any{anyBox{[]int{1}}, anyBox{[]int{1}}, anyBox{1}}, Unique([]any{anyBox{[]int{1}}, anyBox{[]int{1}}, anyBox{1}, anyBox{1}}))
+	is.Eq([]anyBox{{[]int{1}}, {[]int{1}}, {"a"}}, Unique([]anyBox{{[]int{1}}, {[]int{1}}, {"a"}, {"a"}}))
+	is.Eq([][1]any{{[]int{1}}, {[]int{1}}}, Unique([][1]any{{[]int{1}}, {[]int{1}}}))
+
+	// generic
+	is.Eq([]string{"c", "a"}, UniqueOf([]string{"c", "a", "c"}))
+	is.Eq([]int{}, UniqueOf([]int{}))
+}
+
+func TestUniqueBy(t *testing.T) {
+	is := assert.New(t)
+
+	users := []map[string]any{
+		{"id": 1, "name": "tom"},
+		{"id": 2, "name": "john"},
+		{"id": 1, "name": "tom2"},
+		{"name": "no-id"},
+	}
+	is.Eq([]map[string]any{users[0], users[1], users[3]}, UniqueBy(users, "id"))
+
+	ls := []any{
+		map[string]string{"id": "a"},
+		map[string]string{"id": "a"},
+		map[string]any{"id": "a"},
+	}
+	is.Eq([]any{ls[0]}, UniqueBy(ls, "id"))
+
+	type user struct {
+		ID   int
+		name string
+	}
+	us := []*user{{ID: 1}, {ID: 2}, {ID: 1}}
+	is.Eq([]*user{us[0], us[1]}, UniqueBy(us, "ID"))
+	// unexported field
+	is.Len(UniqueBy([]user{{name: "a"}, {name: "a"}}, "name"), 2)
+	is.Eq("invalid", UniqueBy("invalid", "id"))
+
+	// UniqueFold
+	is.Eq([]string{"Go", "php"}, UniqueFold([]string{"Go", "php", "GO", "go", "PHP"}))
+	is.Eq([]any{"Go", 1}, UniqueFold([]any{"Go", 1, "gO", 1}))
+	is.Eq([]any{anyBox{[]int{1}}, anyBox{[]int{1}}, "a"}, UniqueFold([]any{anyBox{[]int{1}}, anyBox{[]int{1}}, "a", "A"}))
+
+	// the key value is not hashable
+	bs := []anyBox{{[]int{1}}, {[]int{1}}, {2}, {2}}
+	is.Eq([]anyBox{bs[0], bs[1], bs[2]}, UniqueBy(bs, "V"))
+	mps := []map[string]any{{"v": anyBox{[]int{1}}}, {"v": anyBox{[]int{1}}}}
+	is.Len(UniqueBy(mps, "v"), 2)
+
+	// use filter name
+	val, err := Apply("uniqueBy", users, []string{"name"})
+	is.NoErr(err)
+	is.Len(val, 4)
+	_, err = Apply("uniqueBy", users, nil)
+	is.ErrMsg(err, "filter: apply 'uniqueBy' error: invalid filter args: expect 1 args, but given 0")
+	val, err = Apply("uniqueFold", []string{"A", "a"}, nil)
+	is.NoErr(err)
+	is.Eq([]string{"A"}, val)
 }
@@ -1,34 +1,143 @@
 package filter
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/gookit/goutil/maputil"
 	"github.com/gookit/goutil/strutil"
 )
 
+// Options for the Filtration
+type Options struct {
+	// StopOnError stop filtering on the first error. default is true
+	//
+	// If is false, will apply all rules and collect all errors, see Filtration.Errors()
+	StopOnError bool
+	// Lenient mode. dont check filter names on add rule, unknown filters
+	// will be skipped on filtering. default is false
+	Lenient bool
+	// BindTag tag name for get field name on bind data to struct. default is "json"
+	BindTag string
+	// BindRaw bind the raw data for the fields that had no rules. default is false
+	BindRaw bool
+	// FlatKeys save filtered value by the flat field key. eg: "user.name"
+	//
+	// default is false, will save to the nested structure mirroring the raw data.
+	FlatKeys bool
+}
+
+// CollectErrors option func. apply all rules and collect all errors.
+func CollectErrors(opt *Options) {
+	opt.StopOnError = false
+}
+
+// LenientMode option func. skip unknown filters, keep compatible with old version.
+func LenientMode(opt *Options) {
+	opt.Lenient = true
+}
+
 // Filtration definition. Sanitization Sanitizing Sanitize
 type Filtration struct {
 	err error
+	// collected errors on filtering
+	errors Errors
+	// options for the filtration
+	opts *Options
 	// raw data
 	data map[string]any
 	// mark has apply filters
 	filtered bool
 	// filtered and clean data
 	cleanData map[string]any
+	// the filtered field paths and values, in the apply order
+	cleanItems []cleanItem
 	// filter rules
 	filterRules []*Rule
+	// custom filters for the filtration
+	filters map[string]ContextFilterFunc
+	// context for filtering, see FilteringContext()
+	ctx context.Context
 }
 
 // New a Filtration
 func New(data map[string]any) *Filtration {
 	return &Filtration{
 		data: data,
+		opts: &Options{StopOnError: true, BindTag: DefaultBindTag},
 		// init map
 		cleanData: make(map[string]any),
+		filters:   make(map[string]ContextFilterFunc),
+	}
+}
+
+// WithOptions set options for the filtration.
+//
+// Usage:
+//
+//	f := filter.New(data).WithOptions(filter.CollectErrors)
+func (f *Filtration) WithOptions(fns ...func(opt *Options)) *Filtration {
+	for _, fn := range fns {
+		fn(f.opts)
+	}
+	return f
+}
+
+// Options get the options
+func (f *Filtration) Options() *Options {
+	return f.opts
+}
+
+// AddFilter add a custom filter func for the filtration.
+// the fn allow type please see the global AddFilter()
+//
+// Will be used before the global custom filters and built-in filters.
+// NOTE: should add filters before add rules, the filter names will be checked on add rule.
+func (f *Filtration) AddFilter(name string, fn any) *Filtration {
+	if name == "" || fn == nil {
+		panic("filter: the filter name and func cannot be empty")
+	}
+
+	f.filters[name] = toFilterFunc(name, fn)
+	return f
+}
+
+// AddFilters add multi custom filter func for the filtration.
+func (f *Filtration) AddFilters(fns map[string]any) *Filtration {
+	for name, fn := range fns {
+		f.AddFilter(name, fn)
+	}
+	return f
+}
+
+// checkFilter check the filter name is exists and the filter args.
+// the args of the filtration filters are checked on apply.
+func (f *Filtration) checkFilter(name string, args []string) error {
+	if _, ok := f.filters[Name(name)]; ok {
+		return nil
 	}
+	return checkFilter(name, args, f.filters, f.opts.Lenient)
+}
+
+// apply a filter by name, will find the filtration filters first.
+func (f *Filtration) apply(ctx context.Context, name string, val any, args []string) (any, error) {
+	if fn, ok := f.filters[Name(name)]; ok {
+		newVal, err := fn(ctx, val, args)
+		if err != nil {
+			return nil, newFilterError(name, val, args, err)
+		}
+		return newVal, nil
+	}
+
+	// lenient mode: skip unknown filter
+	if f.opts.Lenient && !HasFilter(name) {
+		return val, nil
+	}
+	return ApplyContext(ctx, name, val, args)
 }
 
 // LoadData set raw data for filtering.
@@ -39,6 +148,7 @@ func (f *Filtration) LoadData(data map[string]any) {
 // ResetData reset raw and filtered data
 func (f *Filtration) ResetData(resetRaw bool) {
 	f.err = nil
+	f.errors = nil
 	f.filtered = false
 
 	// reset data.
@@ -47,18 +157,21 @@ func (f *Filtration) ResetData(resetRaw bool) {
 	}
 
 	f.cleanData = make(map[string]any)
+	f.cleanItems = nil
 }
 
 // ResetRules reset rules and filtered data
 func (f *Filtration) ResetRules() {
 	f.err = nil
+	f.errors = nil
 	f.filtered = false
 
-	// clear rules
-	f.filterRules = f.filterRules[:0]
+	// clear rules. NOTE: dont reuse the slice, it maybe shared by Schema
+	f.filterRules = nil
 
 	// clear cleanData
 	f.cleanData = make(map[string]any)
+	f.cleanItems = nil
 }
 
 // Clear all data and rules
@@ -71,32 +184,38 @@ func (f *Filtration) Clear() {
  * add rules and filtering data
  *************************************************************/
 
-// AddRule add filter(s) rule.
+// AddRule add filter(s) rule. will panic on the filter name is not exists
+// or the filter args are invalid.
+//
+// The rule allow type: string, func(any) (any, error) and FieldFunc.
 //
 // Usage:
 //
 //	f.AddRule("name", "trim")
 //	f.AddRule("age", "int")
 //	f.AddRule("age", "trim|int")
+//	f.AddRule("username", func(val any, data filter.DataView) (any, error) {
+//		return data.String("email"), nil
+//	})
 func (f *Filtration) AddRule(field string, rule any) *Rule {
 	fields := strutil.Split(field, ",")
 	if len(fields) == 0 {
-		panic("filter: invalid fields parameters, cannot be empty")
+		panic(errEmptyFields)
 	}
 
 	r := newRule(fields)
+	r.checkFn = f.checkFilter
 
 	if strRule, ok := rule.(string); ok {
-		strRule = strings.TrimSpace(strRule)
-		rules := strutil.Split(strings.Trim(strRule, "|:"), "|")
-
-		if len(rules) == 0 {
-			panic("filter: invalid 'rule' params, cannot be empty")
+		if err := r.parse(strRule); err != nil {
+			panic(err)
 		}
-
-		r.AddFilters(rules...)
 	} else if fn, ok := rule.(func(any) (any, error)); ok {
 		r.SetFilterFunc(fn)
+	} else if fn, ok := rule.(FieldFunc); ok {
+		r.AddFieldFunc(fn)
+	} else if fn, ok := rule.(func(any, DataView) (any, error)); ok {
+		r.AddFieldFunc(fn)
 	} else {
 		panic("filter: 'rule' params cannot be empty and type allow: string, func")
 	}
@@ -105,7 +224,7 @@ func (f *Filtration) AddRule(field string, rule any) *Rule {
 	return r
 }
 
-// AddRules add multi rules.
+// AddRules add multi rules. the rules will be added in the sorted order of the fields.
 //
 // Usage:
 //
@@ -114,8 +233,35 @@ func (f *Filtration) AddRule(field string, rule any) *Rule {
 //		"age": "trim|int",
 //	})
 func (f *Filtration) AddRules(rules map[string]string) *Filtration {
-	for field, rule := range rules {
-		f.AddRule(field, rule)
+	fields := make([]string, 0, len(rules))
+	for field := range rules {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		f.AddRule(field, rules[field])
+	}
+	return f
+}
+
+// FieldRule a field and rule string pair. for add rules in order.
+type FieldRule struct {
+	Field string
+	Rule  string
+}
+
+// AddRuleList add multi rules in the given order.
+//
+// Usage:
+//
+//	f.AddRuleList(
+//		filter.FieldRule{Field: "name", Rule: "trim|lower"},
+//		filter.FieldRule{Field: "age", Rule: "trim|int"},
+//	)
+func (f *Filtration) AddRuleList(rules ...FieldRule) *Filtration {
+	for _, fr := range rules {
+		f.AddRule(fr.Field, fr.Rule)
 	}
 	return f
 }
@@ -126,23 +272,159 @@ func (f *Filtration) Sanitize() error {
 }
 
 // Filtering apply all filter rules, filtering data
+//
+// Apply order of the rules:
+//   - the rules with dependencies are applied after the depended rules. see Rule.After()
+//   - the normal rules are applied before the cross-field rules. see Rule.AddFieldFunc()
+//   - the rules with higher priority are applied first. see Rule.Priority()
+//   - otherwise, the rules are applied in the added order.
+//
+// On the option StopOnError is false, will apply all rules and
+// return the collected Errors.
 func (f *Filtration) Filtering() error {
+	return f.FilteringContext(context.Background())
+}
+
+// FilteringContext apply all filter rules with the context, the ctx will be
+// passed to the context-aware filters. see ContextFilterFunc
+//
+// Will stop filtering on the ctx is done, the error can be checked by
+// errors.Is(err, context.Canceled). the ctx is done before apply a rule,
+// the error filter name of the rule fields is "context".
+func (f *Filtration) FilteringContext(ctx context.Context) error {
 	if f.filtered || f.err != nil {
 		return f.err
 	}
 
+	order, err := f.ruleOrder()
+	if err != nil {
+		f.err = err
+		return err
+	}
+
+	f.ctx = ctx
+	defer func() { f.ctx = nil }()
+
 	// apply rule to validate data.
-	for _, rule := range f.filterRules {
-		if err := rule.Apply(f); err != nil { // has error
-			f.err = err
+	for _, i := range order {
+		rule := f.filterRules[i]
+		if err := ctx.Err(); err != nil { // ctx is done
+			f.addError(i, rule.contextError(err))
 			break
 		}
+
+		if err := rule.Apply(f); err != nil { // has error
+			f.addError(i, err)
+			if f.opts.StopOnError || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+
+	if len(f.errors) > 0 {
+		if f.opts.StopOnError {
+			f.err = f.errors[0]
+		} else {
+			f.err = f.errors
+		}
 	}
 
 	f.filtered = true
 	return f.err
 }
 
+// ruleOrder get the apply order of the rules, returns the rule indexes.
+// see Filtering() for the order details.
+func (f *Filtration) ruleOrder() ([]int, error) {
+	rules := f.filterRules
+	// the base order: normal rules first, then by priority and added order.
+	base := make([]int, len(rules))
+	for i := range base {
+		base[i] = i
+	}
+	sort.SliceStable(base, func(a, b int) bool {
+		ra, rb := rules[base[a]], rules[base[b]]
+		if ra.crossField != rb.crossField {
+			return !ra.crossField
+		}
+		return ra.priority > rb.priority
+	})
+
+	// the depended rule indexes of each rule
+	deps := make([][]int, len(rules))
+	for i, rule := range rules {
+		for _, field := range rule.after {
+			for j, other := range rules {
+				if j != i && other.hasField(field) {
+					deps[i] = append(deps[i], j)
+				}
+			}
+		}
+	}
+
+	// topological sort, pick the first ready rule in the base order each time.
+	order := make([]int, 0, len(rules))
+	done := make([]bool, len(rules))
+	for len(order) < len(rules) {
+		next := -1
+		for _, i := range base {
+			if !done[i] && depsDone(deps[i], done) {
+				next = i
+				break
+			}
+		}
+
+		// has cycle
+		if next < 0 {
+			var fields []string
+			for _, i := range base {
+				if !done[i] {
+					fields = append(fields, strings.Join(rules[i].fields, ","))
+				}
+			}
+			return nil, fmt.Errorf("filter: %w, the unresolved rule fields: %s", ErrRuleCycle, strings.Join(fields, "; "))
+		}
+
+		done[next] = true
+		order = append(order, next)
+	}
+	return order, nil
+}
+
+func depsDone(deps []int, done []bool) bool {
+	for _, i := range deps {
+		if !done[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *Filtration) addError(index int, err error) {
+	var es Errors
+	switch typErr := err.(type) {
+	case *FilterError:
+		es = Errors{typErr}
+	case Errors:
+		es = typErr
+	default:
+		es = Errors{&FilterError{Err: err}}
+	}
+
+	for _, fe := range es {
+		fe.Index = index
+		f.errors = append(f.errors, fe)
+	}
+}
+
+// context get the filtering context
+func (f *Filtration) context() context.Context {
+	if f.ctx == nil {
+		return context.Background()
+	}
+	return f.ctx
+}
+
 // IsOK of to apply filters
 func (f *Filtration) IsOK() bool {
 	return f.err == nil
@@ -153,6 +435,11 @@ func (f *Filtration) Err() error {
 	return f.err
 }
 
+// Errors get all collected errors
+func (f *Filtration) Errors() Errors {
+	return f.errors
+}
+
 /*************************************************************
  * get raw/filtered data value
  *************************************************************/
@@ -164,25 +451,79 @@ func (f *Filtration) Raw(key string) (any, bool) {
 
 // Safe get filtered value by key
 func (f *Filtration) Safe(key string) (any, bool) {
-	return maputil.GetByPath(key, f.cleanData)
+	return f.cleanVal(key)
 }
 
 // SafeVal get filtered value by key
 func (f *Filtration) SafeVal(key string) any {
-	val, _ := maputil.GetByPath(key, f.cleanData)
+	val, _ := f.cleanVal(key)
 	return val
 }
 
-// Get value by key
+// Get value by key, will read the filtered value first, then the raw value.
+//
+// On the value is a partly filtered map or slice, the raw values of the
+// unfiltered keys will be merged. eg: only "user.name" has rule, Get("user")
+// also contains the raw "user.age"
 func (f *Filtration) Get(key string) (any, bool) {
-	val, ok := maputil.GetByPath(key, f.cleanData)
+	val, ok := f.cleanVal(key)
 	if !ok {
-		val, ok = maputil.GetByPath(key, f.data)
+		return maputil.GetByPath(key, f.data)
+	}
+
+	if isContainer(val) {
+		val = f.mergeRaw(key, val)
 	}
+	return val, true
+}
 
+// cleanVal get the filtered value by key. the nil placeholder in the filtered
+// slice is not exists. eg: only "items.1.name" is filtered, "items.0" is nil.
+func (f *Filtration) cleanVal(key string) (any, bool) {
+	val, ok := maputil.GetByPath(key, f.cleanData)
+	if ok && val == nil && !f.opts.FlatKeys && !f.isFiltered(key) {
+		return nil, false
+	}
 	return val, ok
 }
 
+// isFiltered check the key, its parent or children have been filtered.
+func (f *Filtration) isFiltered(key string) bool {
+	for _, item := range f.cleanItems {
+		if item.path == key || strings.HasPrefix(key, item.path+".") || strings.HasPrefix(item.path, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRaw merge the raw values to the filtered container value of the key.
+// the raw data will not be changed.
+func (f *Filtration) mergeRaw(key string, val any) any {
+	rawVal, ok := maputil.GetByPath(key, f.data)
+	if !ok || !isContainer(rawVal) {
+		return val
+	}
+
+	prefix := key + "."
+	var items []cleanItem
+	for _, item := range f.cleanItems {
+		// the whole value has been filtered
+		if item.path == key || strings.HasPrefix(prefix, item.path+".") {
+			return val
+		}
+		if strings.HasPrefix(item.path, prefix) {
+			items = append(items, item)
+		}
+	}
+
+	merged := deepCopy(rawVal)
+	for _, item := range items {
+		merged = setByKeys(merged, strings.Split(item.path[len(prefix):], "."), item.val, rawVal)
+	}
+	return merged
+}
+
 // MustGet value by key
 func (f *Filtration) MustGet(key string) any {
 	val, _ := f.Get(key)
@@ -205,13 +546,52 @@ func (f *Filtration) Int64(key string) int64 {
 	return 0
 }
 
-// Bool value get from the filtered data.
+// Bool value get from the filtered data. will convert the value to bool.
 func (f *Filtration) Bool(key string) bool {
-	if val, ok := f.Safe(key); ok {
-		return val.(bool)
-	}
+	val, _ := GetAs[bool](f, key)
+	return val
+}
 
-	return false
+// Uint get an uint value from filtered data.
+func (f *Filtration) Uint(key string) uint {
+	val, _ := GetAs[uint](f, key)
+	return val
+}
+
+// Float get a float value from filtered data.
+func (f *Filtration) Float(key string) float64 {
+	val, _ := GetAs[float64](f, key)
+	return val
+}
+
+// Time get a time value from filtered data. will parse the string value.
+func (f *Filtration) Time(key string) time.Time {
+	val, _ := GetAs[time.Time](f, key)
+	return val
+}
+
+// Duration get a duration value from filtered data. eg: "1h30m"
+func (f *Filtration) Duration(key string) time.Duration {
+	val, _ := GetAs[time.Duration](f, key)
+	return val
+}
+
+// Strings get a string slice from filtered data.
+func (f *Filtration) Strings(key string) []string {
+	val, _ := GetAs[[]string](f, key)
+	return val
+}
+
+// Ints get an int slice from filtered data.
+func (f *Filtration) Ints(key string) []int {
+	val, _ := GetAs[[]int](f, key)
+	return val
+}
+
+// Map get a map value from filtered data.
+func (f *Filtration) Map(key string) map[string]any {
+	val, _ := GetAs[map[string]any](f, key)
+	return val
 }
 
 // String get a string value from filtered data.
@@ -228,129 +608,94 @@ func (f *Filtration) String(key string) string {
 	return fmt.Sprint(val)
 }
 
-// BindStruct bind the filtered data to struct.
-func (f *Filtration) BindStruct(ptr any) error {
-	bts, err := json.Marshal(f.cleanData)
-	if err != nil {
-		return err
+// GetAs get a value from the filtered data and convert to the type T.
+//
+// Returns ErrKeyNotFound on the key is not exists, ErrTypeMismatch on convert failed.
+//
+// Usage:
+//
+//	age, err := filter.GetAs[int](f, "age")
+//	tags, err := filter.GetAs[[]string](f, "tags")
+func GetAs[T any](f *Filtration, key string) (T, error) {
+	var zero T
+	val, ok := f.Safe(key)
+	if !ok {
+		return zero, fmt.Errorf("filter: get '%s' error: %w", key, ErrKeyNotFound)
 	}
 
-	return json.Unmarshal(bts, ptr)
-}
-
-// RawData get raw data
-func (f *Filtration) RawData() map[string]any {
-	return f.data
-}
-
-// CleanData get filtered data
-func (f *Filtration) CleanData() map[string]any {
-	return f.cleanData
-}
-
-/*************************************************************
- * filtering rule
- *************************************************************/
-
-// Rule definition
-type Rule struct {
-	// fields to filter
-	fields []string
-	// filter name list
-	filters []string
-	// filter args. { index: "args" }
-	filterArgs map[int]string
-	// user custom filter func
-	filterFunc func(val any) (any, error)
-	// default value for the rule
-	defaultVal any
-}
-
-func newRule(fields []string) *Rule {
-	return &Rule{
-		fields: fields,
-		// init map
-		filterArgs: make(map[int]string),
+	if tv, ok := val.(T); ok {
+		return tv, nil
 	}
-}
 
-// SetDefaultVal set default value for the rule
-func (r *Rule) SetDefaultVal(defaultVal any) *Rule {
-	r.defaultVal = defaultVal
-	return r
+	rv, err := convType(val, reflect.TypeOf(&zero).Elem())
+	if err != nil {
+		return zero, fmt.Errorf("filter: get '%s' error: %w: %s", key, ErrTypeMismatch, err.Error())
+	}
+	return rv.Interface().(T), nil
 }
 
-// SetFilterFunc user custom filter func
-func (r *Rule) SetFilterFunc(fn func(val any) (any, error)) *Rule {
-	r.filterFunc = fn
-	return r
+// MustGetAs like GetAs, but will panic on error.
+func MustGetAs[T any](f *Filtration, key string) T {
+	val, err := GetAs[T](f, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
 }
 
-// AddFilters add multi filter(s).
-//
-// Usage:
+// BindStruct bind the filtered data to struct.
 //
-//	r.AddFilters("int", "str2arr:,")
-func (r *Rule) AddFilters(filters ...string) *Rule {
-	for _, filterName := range filters {
-		pos := strings.IndexRune(filterName, ':')
-		if pos > 0 { // has filter args
-			name := filterName[:pos]
-			index := len(r.filters)
-			r.filters = append(r.filters, name)
-			r.filterArgs[index] = filterName[pos+1:]
-		} else {
-			r.filters = append(r.filters, filterName)
-		}
+// Use the option BindTag for get field name, will use weak type
+// converting on the value type is not match the field.
+// On the option BindRaw is true, will bind raw data for the fields that had no rules.
+func (f *Filtration) BindStruct(ptr any) error {
+	data := f.cleanData
+	if f.opts.BindRaw {
+		// merge by path, keep the raw values of the partly filtered map. eg: "user.name"
+		data = f.MergedData()
 	}
 
-	return r
+	b := &binder{tagName: f.opts.BindTag}
+	return b.Bind(data, ptr)
 }
 
-// Apply rule for the rule fields
-func (r *Rule) Apply(f *Filtration) (err error) {
-	// validate field
-	for _, field := range r.Fields() {
-		// get field value.
-		val, has := f.Get(field)
-		if !has { // no field
-			if r.defaultVal == nil {
-				continue
-			}
-
-			// has default value
-			val = r.defaultVal
-		}
-
-		// custom filter func
-		if r.filterFunc != nil {
-			val, err = r.filterFunc(val)
-			if err != nil {
-				return err
-			}
-
-			// save filtered value.
-			f.cleanData[field] = val
-			continue
-		}
+// a filtered field path and value
+type cleanItem struct {
+	path string
+	val  any
+}
 
-		// call built-in filters
-		for i, name := range r.filters {
-			args := parseArgString(r.filterArgs[i])
-			val, err = Apply(name, val, args)
-			if err != nil {
-				return err
-			}
-		}
+// set filtered value to the clean data
+func (f *Filtration) setClean(field string, val any) {
+	f.cleanItems = append(f.cleanItems, cleanItem{path: field, val: val})
 
-		// save filtered value.
+	if f.opts.FlatKeys {
 		f.cleanData[field] = val
+	} else {
+		setByPath(f.cleanData, f.data, field, val)
 	}
+}
+
+// RawData get raw data
+func (f *Filtration) RawData() map[string]any {
+	return f.data
+}
 
-	return
+// CleanData get filtered data
+//
+// The slice keeps the positions of the raw data, the positions that are
+// not filtered will be nil. eg: only "items.1.name" is filtered, the
+// "items" will be []any{nil, map[string]any{"name": "b"}}
+func (f *Filtration) CleanData() map[string]any {
+	return f.cleanData
 }
 
-// Fields name get
-func (r *Rule) Fields() []string {
-	return r.fields
+// MergedData get the raw data merged with the filtered values.
+// the raw data will not be changed.
+func (f *Filtration) MergedData() map[string]any {
+	data := deepCopy(f.data).(map[string]any)
+	for _, item := range f.cleanItems {
+		setByPath(data, f.data, item.path, item.val)
+	}
+	return data
 }
@@ -1,9 +1,12 @@
 package filter
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gookit/goutil/testutil/assert"
 )
@@ -148,19 +151,34 @@ func TestFiltration_AddRule(t *testing.T) {
 	is.NoErr(f.Filtering())
 	is.Eq("def val", f.String("not-exist"))
 
+	// the trailing spaces of the rule are ignored
+	f = New(map[string]any{"tags": "a,b", "name": " tom "})
+	f.AddRule("tags", "str2arr:, ")
+	f.AddRule("name", "trim: ")
+	is.NoErr(f.Filtering())
+	is.Eq([]string{"a", "b"}, f.Strings("tags"))
+	is.Eq("tom", f.String("name"))
+
 	// trimStrings error
 	f = New(map[string]any{
 		"ints": []int{1, 2, 3},
 	})
 	f.AddRule("ints", "trimStrings")
 	is.Err(f.Filtering())
-	is.Eq("invalid input parameter", f.Err().Error())
+	is.True(errors.Is(f.Err(), ErrTypeMismatch))
+	is.Eq("filter: field 'ints' apply 'trimStrings' error: value type mismatch: only use for []string type, input []int", f.Err().Error())
 
 	// stringsToInts error
 	f.ResetRules()
 	f.AddRule("ints", "stringsToInts")
 	is.Err(f.Filtering())
-	is.Eq("invalid input parameter", f.Err().Error())
+	is.True(errors.Is(f.Err(), ErrTypeMismatch))
+
+	var fe *FilterError
+	is.True(errors.As(f.Err(), &fe))
+	is.Eq("ints", fe.Field)
+	is.Eq("stringsToInts", fe.Filter)
+	is.Eq([]int{1, 2, 3}, fe.Value)
 }
 
 func TestFiltration_Filtering(t *testing.T) {
@@ -205,7 +223,7 @@ func TestFiltration_Filtering(t *testing.T) {
 	is.Eq(int64(0), f.Int64("not-exist"))
 	is.Eq(50.34, f.MustGet("money"))
 	is.Eq([]int{1, 2}, f.MustGet("sub1"))
-	is.Len(f.MustGet("ids"), 2)
+	is.Eq([]int{1, 2}, f.MustGet("ids"))
 	is.Eq([]string{"go", "lib"}, f.MustGet("tags"))
 	is.Eq("INHERE", f.CleanData()["name"])
 	is.Eq("word", f.String("str1"))
@@ -271,3 +289,719 @@ func TestFiltration_Filtering(t *testing.T) {
 	is.Eq(89, user.Age)
 	is.Eq("Inhere", user.Name)
 }
+
+func TestFiltration_AddFilter(t *testing.T) {
+	is := assert.New(t)
+
+	f := New(map[string]any{"name": " inhere ", "tags": "a-b"})
+	f.AddFilter("wrap", func(val any, args []string) (any, error) {
+		return args[0] + val.(string) + args[0], nil
+	})
+	f.AddFilters(map[string]any{
+		// override the built-in filter
+		"upper": func(val any, _ []string) (any, error) {
+			return "UP:" + val.(string), nil
+		},
+	})
+
+	f.AddRule("name", "trim|wrap:*")
+	f.AddRule("tags", "uppercase")
+	is.NoErr(f.Filtering())
+	is.Eq("*inhere*", f.String("name"))
+	is.Eq("UP:a-b", f.String("tags"))
+
+	is.Panics(func() {
+		f.AddFilter("empty", nil)
+	})
+}
+
+func TestFiltration_CollectErrors(t *testing.T) {
+	is := assert.New(t)
+
+	data := map[string]any{
+		"name": "inhere",
+		"age":  "abc",
+		"ids":  []int{1, 2},
+		"tags": 234,
+	}
+
+	// default stop on first error
+	f := New(data)
+	f.AddRule("age", "int")
+	f.AddRule("ids,tags", "trimStrings")
+	is.Err(f.Filtering())
+	is.Len(f.Errors(), 1)
+	is.True(f.Errors().HasField("age"))
+
+	f = New(data).WithOptions(CollectErrors)
+	is.False(f.Options().StopOnError)
+	f.AddRule("name", "upper")
+	f.AddRule("age", "int")
+	f.AddRule("ids,tags", "trimStrings")
+	f.AddRule("tags", func(val any) (any, error) {
+		return nil, ErrBadArgs
+	})
+
+	err := f.Filtering()
+	is.Err(err)
+	es, ok := err.(Errors)
+	is.True(ok)
+	is.Len(es, 4)
+	is.Eq("INHERE", f.String("name"))
+	is.Eq([]string{"age", "ids", "tags"}, []string{es[0].Field, es[1].Field, es[2].Field})
+	is.Eq("int", es[0].Filter)
+	is.Eq("abc", es[0].Value)
+	is.Eq("func", es[3].Filter)
+	is.Eq(3, es[3].Index)
+	is.Len(es.Field("tags"), 2)
+	is.False(f.IsOK())
+	is.Eq(es, f.Errors())
+
+	f.ResetRules()
+	is.Empty(f.Errors())
+	is.NoErr(f.Err())
+}
+
+func TestFiltration_unknownFilter(t *testing.T) {
+	is := assert.New(t)
+
+	f := New(map[string]any{"name": " Inhere ", "age": 23})
+	is.PanicsErrMsg(func() {
+		f.AddRule("name", "trim|lowr")
+	}, "filter: unknown filter 'lowr', did you mean 'lower'?")
+	is.PanicsErrMsg(func() {
+		f.AddRule("name", "trim").AddFilters("upperFrist")
+	}, "filter: unknown filter 'upperFrist', did you mean 'upperFirst'?")
+	is.PanicsErrMsg(func() {
+		f.AddRule("name", "notExistFilter")
+	}, "filter: unknown filter 'notExistFilter'")
+
+	// invalid args
+	is.PanicsErrMsg(func() {
+		f.AddRule("name", "trim|substr:1")
+	}, "filter: check 'substr' error: invalid filter args: expect 2 args, but given 1")
+	is.PanicsErrMsg(func() {
+		f.AddRule("name", "trim").AddFilters("lower:a")
+	}, "filter: check 'lower' error: invalid filter args: expect 0 args, but given 1")
+
+	// custom filter name
+	f.AddFilter("normalize", func(s string) string { return s })
+	is.PanicsErrMsg(func() {
+		f.AddRule("name", "normalise")
+	}, "filter: unknown filter 'normalise', did you mean 'normalize'?")
+	// the filtration filter can override the built-in filter args
+	f.AddFilter("substr", func(s string, n int) string { return s[n:] })
+	f.AddRule("name", "trim|substr:1")
+
+	// lenient mode
+	f = New(map[string]any{"name": " Inhere ", "age": 23}).WithOptions(LenientMode)
+	f.AddRule("name", "trim|lowr")
+	f.AddRule("age", "lowr")
+	is.NoErr(f.Filtering())
+	is.Eq("Inhere", f.String("name"))
+	is.Eq(23, f.SafeVal("age"))
+}
+
+func TestFiltration_nestedCleanData(t *testing.T) {
+	is := assert.New(t)
+
+	data := map[string]any{
+		"user":  map[string]any{"name": " inhere ", "age": "23"},
+		"items": []any{map[string]any{"sku": " a "}},
+	}
+	f := New(data)
+	f.AddRule("user.name", "trim|upper")
+	f.AddRule("user.age", "int")
+	f.AddRule("items.0.sku", "trim")
+	is.NoErr(f.Filtering())
+
+	is.Eq(map[string]any{
+		"user":  map[string]any{"name": "INHERE", "age": 23},
+		"items": []any{map[string]any{"sku": "a"}},
+	}, f.CleanData())
+	is.Eq("INHERE", f.String("user.name"))
+	is.Eq("a", f.String("items.0.sku"))
+
+	// flat keys
+	f = New(data).WithOptions(func(opt *Options) {
+		opt.FlatKeys = true
+	})
+	f.AddRule("user.name", "trim")
+	is.NoErr(f.Filtering())
+	is.Eq(map[string]any{"user.name": "inhere"}, f.CleanData())
+}
+
+func TestFiltration_Get_partlyFiltered(t *testing.T) {
+	is := assert.New(t)
+
+	data := map[string]any{
+		"user":  map[string]any{"name": " tom ", "age": "23"},
+		"items": []any{map[string]any{"sku": " a ", "qty": 1}, map[string]any{"sku": " b "}},
+		"tags":  []any{" a ", "b"},
+	}
+	f := New(data)
+	f.AddRule("user.name", "trim")
+	f.AddRule("items.0.sku", "trim")
+	f.AddRule("tags", func(val any) (any, error) {
+		return []any{"a"}, nil
+	})
+	f.AddRule("tags.0", "upper")
+	// the later rule can read the raw values of the partly filtered map
+	f.AddRule("level", "int").SetDefaultVal("1").When(func(_ any, f *Filtration) bool {
+		user, _ := f.Get("user")
+		return user.(map[string]any)["age"] == "23"
+	})
+	is.NoErr(f.Filtering())
+
+	user, ok := f.Get("user")
+	is.True(ok)
+	is.Eq(map[string]any{"name": "tom", "age": "23"}, user)
+	is.Eq(1, f.Int("level"))
+
+	items, _ := f.Get("items")
+	is.Eq([]any{map[string]any{"sku": "a", "qty": 1}, map[string]any{"sku": " b "}}, items)
+	item, _ := f.Get("items.0")
+	is.Eq(map[string]any{"sku": "a", "qty": 1}, item)
+
+	// the whole value has been filtered
+	tags, _ := f.Get("tags")
+	is.Eq([]any{"A"}, tags)
+
+	// the raw and clean data are not changed
+	is.Eq(" tom ", data["user"].(map[string]any)["name"])
+	is.Eq(map[string]any{"name": "tom"}, f.SafeVal("user"))
+}
+
+func TestFiltration_wildcardPath(t *testing.T) {
+	is := assert.New(t)
+
+	f := New(map[string]any{
+		"items": []any{
+			map[string]any{"name": " a ", "sku": " s0 "},
+			map[string]any{"name": " b ", "sku": " s1 "},
+		},
+		"user": map[string]any{
+			"name": " inhere ",
+			"info": map[string]any{"name": " sub "},
+		},
+	})
+	f.AddRule("items.*.name", "trim|upper")
+	f.AddRule("items.0.sku", "trim")
+	f.AddRule("user.**.name", "trim")
+	is.NoErr(f.Filtering())
+
+	is.Eq(map[string]any{
+		"items": []any{
+			map[string]any{"name": "A", "sku": "s0"},
+			map[string]any{"name": "B"},
+		},
+		"user": map[string]any{
+			"name": "inhere",
+			"info": map[string]any{"name": "sub"},
+		},
+	}, f.CleanData())
+
+	// the positions that are not filtered are nil placeholders
+	f = New(map[string]any{
+		"items": []any{map[string]any{"id": 1}, map[string]any{"name": " b "}},
+	})
+	f.AddRule("items.*.name", "trim")
+	is.NoErr(f.Filtering())
+	is.Eq(map[string]any{
+		"items": []any{nil, map[string]any{"name": "b"}},
+	}, f.CleanData())
+	_, ok := f.Safe("items.0")
+	is.False(ok)
+	is.Nil(f.SafeVal("items.0"))
+	is.Eq("b", f.SafeVal("items.1.name"))
+	// Get the raw value of the placeholder
+	item, ok := f.Get("items.0")
+	is.True(ok)
+	is.Eq(map[string]any{"id": 1}, item)
+
+	// the filtered nil value is exists
+	f = New(map[string]any{"items": []any{" a "}})
+	f.AddRule("items.0", func(val any) (any, error) {
+		return nil, nil
+	})
+	is.NoErr(f.Filtering())
+	_, ok = f.Safe("items.0")
+	is.True(ok)
+
+	// error field is the real path
+	f = New(map[string]any{
+		"items": []any{map[string]any{"age": "1"}, map[string]any{"age": "abc"}},
+	})
+	f.AddRule("items.*.age", "int")
+	is.Err(f.Filtering())
+	is.Eq("items.1.age", f.Errors()[0].Field)
+}
+
+func TestFiltration_quotedArgs(t *testing.T) {
+	is := assert.New(t)
+
+	f := New(map[string]any{
+		"tags": " a|b|c ",
+		"code": ",|abc|,",
+		"time": "12:34",
+	})
+	f.AddRule("tags", `trim | strToSlice:"|"`)
+	f.AddRule("code", `trim:\,\|`)
+	f.AddRule("time", `str2time:"15:04"`)
+	is.NoErr(f.Filtering())
+	is.Eq([]string{"a", "b", "c"}, f.SafeVal("tags"))
+	is.Eq("abc", f.String("code"))
+	is.StrContains(f.String("time"), "12:34:00")
+
+	is.PanicsErrMsg(func() {
+		f.AddRule("code", `trim:"abc`)
+	}, `filter: parse rule "trim:\"abc" error at column 6: unterminated quoted arg`)
+}
+
+func TestFiltration_condition(t *testing.T) {
+	is := assert.New(t)
+
+	f := New(map[string]any{
+		"name":  " INHERE ",
+		"age":   23,
+		"empty": "",
+		"type":  "numeric",
+		"value": " 34 ",
+		"tags":  "a;b",
+	})
+	f.AddRule("name", "if:isString(trim|lower)")
+	f.AddRule("age", "if:isString(trim|lower)")
+	f.AddRule("empty", "if:notEmpty(int)")
+	f.AddRule("value", "trim|if:fieldEq:type,numeric(int)")
+	f.AddRule("tags", "if:notEmpty(str2arr:;)|if:isSlice(unique)")
+	is.NoErr(f.Filtering())
+
+	is.Eq("inhere", f.String("name"))
+	is.Eq(23, f.SafeVal("age"))
+	is.Eq("", f.SafeVal("empty"))
+	is.Eq(34, f.SafeVal("value"))
+	is.Eq([]string{"a", "b"}, f.SafeVal("tags"))
+
+	// rule When
+	f = New(map[string]any{"type": "text", "value": " 34 ", "other": " abc "})
+	f.AddRule("value", "trim|int").When(func(val any, f *Filtration) bool {
+		return f.String("type") == "numeric"
+	})
+	f.AddRule("other", "trim").When(func(val any, f *Filtration) bool {
+		return val != ""
+	})
+	is.NoErr(f.Filtering())
+	_, ok := f.Safe("value")
+	is.False(ok)
+	is.Eq("abc", f.String("other"))
+
+	// error in cond group
+	f = New(map[string]any{"value": "abc"})
+	f.AddRule("value", "if:isString(trim|int)")
+	is.Err(f.Filtering())
+	is.Eq("int", f.Errors()[0].Filter)
+	is.Eq("value", f.Errors()[0].Field)
+
+	// unknown names
+	is.PanicsErrMsg(func() {
+		f.AddRule("value", "if:notExist(trim)")
+	}, "filter: unknown filter condition 'notExist'")
+	is.PanicsErrMsg(func() {
+		f.AddRule("value", "if:notEmpty(lowr)")
+	}, "filter: unknown filter 'lowr', did you mean 'lower'?")
+}
+
+func TestFiltration_defaultMode(t *testing.T) {
+	is := assert.New(t)
+	data := map[string]any{
+		"nil":   nil,
+		"empty": "",
+		"space": "  ",
+	}
+
+	// DefaultOnMissing: only missing field use default value
+	f := New(data)
+	f.AddRule("empty,space,missing", "trim").SetDefaultVal("def")
+	is.NoErr(f.Filtering())
+	is.Eq("", f.SafeVal("empty"))
+	is.Eq("", f.SafeVal("space"))
+	is.Eq("def", f.SafeVal("missing"))
+
+	// DefaultOnNil
+	f = New(data)
+	f.AddRule("nil,empty", "trim").SetDefaultVal("def").SetDefaultMode(DefaultOnNil)
+	is.NoErr(f.Filtering())
+	is.Eq("def", f.SafeVal("nil"))
+	is.Eq("", f.SafeVal("empty"))
+
+	// DefaultOnEmpty
+	f = New(data)
+	f.AddRule("nil,empty,space", "trim").SetDefaultVal("def").SetDefaultMode(DefaultOnEmpty)
+	is.NoErr(f.Filtering())
+	is.Eq("def", f.SafeVal("nil"))
+	is.Eq("def", f.SafeVal("empty"))
+	is.Eq("", f.SafeVal("space"))
+
+	// DefaultAfterFilter
+	f = New(data)
+	f.AddRule("empty,space,missing", "trim").SetDefaultVal("def").SetDefaultMode(DefaultAfterFilter)
+	is.NoErr(f.Filtering())
+	is.Eq("def", f.SafeVal("empty"))
+	is.Eq("def", f.SafeVal("space"))
+	is.Eq("def", f.SafeVal("missing"))
+
+	// default in rule string
+	f = New(data)
+	f.AddRule("space,missing", "trim|default:guest")
+	f.AddRule("name", "trim|default:'a, b'|upper")
+	is.NoErr(f.Filtering())
+	is.Eq("guest", f.SafeVal("space"))
+	is.Eq("guest", f.SafeVal("missing"))
+	is.Eq("A, B", f.SafeVal("name"))
+
+	val, err := Apply("default", "", []string{"abc"})
+	is.NoErr(err)
+	is.Eq("abc", val)
+	_, err = Apply("default", "", nil)
+	is.True(errors.Is(err, ErrBadArgs))
+}
+
+func TestRule_Required(t *testing.T) {
+	is := assert.New(t)
+
+	f := New(map[string]any{"name": " ", "age": "23"})
+	f.AddRule("name", "trim").Required()
+	f.AddRule("age,role", "trim").Required()
+	err := f.Filtering()
+	is.True(errors.Is(err, ErrRequired))
+	is.ErrMsg(err, "filter: field 'role' apply 'required' error: value is required")
+
+	// the empty value after filter is allowed
+	f = New(map[string]any{"name": " "})
+	f.AddRule("name", "trim").Required()
+	f.AddRule("role", "trim").SetDefaultVal("guest").Required()
+	is.NoErr(f.Filtering())
+	is.Eq("", f.String("name"))
+	is.Eq("guest", f.String("role"))
+
+	// not applied on the condition is false
+	f = New(map[string]any{"type": "text"})
+	f.AddRule("value", "int").Required().When(func(val any, f *Filtration) bool {
+		return f.String("type") == "numeric"
+	})
+	is.NoErr(f.Filtering())
+}
+
+func TestRule_AddFunc(t *testing.T) {
+	is := assert.New(t)
+
+	f := New(map[string]any{"age": " +23 ", "name": " inhere "})
+	f.AddRule("age", "trim").AddFunc(func(val any) (any, error) {
+		return strings.TrimPrefix(val.(string), "+"), nil
+	}).AddFilters("int")
+	f.AddRule("name", "trim").AddFunc(func(val any) (any, error) {
+		return val.(string) + "!", nil
+	}).AddFilters("upper")
+	is.NoErr(f.Filtering())
+	is.Eq(23, f.SafeVal("age"))
+	is.Eq("INHERE!", f.SafeVal("name"))
+
+	// func error
+	f = New(map[string]any{"age": " 23 "})
+	f.AddRule("age", "trim").AddFunc(func(val any) (any, error) {
+		return nil, errors.New("func error")
+	}).AddFilters("int")
+	is.Err(f.Filtering())
+	is.Eq("func", f.Errors()[0].Filter)
+	is.Eq("23", f.Errors()[0].Value)
+	is.Eq("filter: field 'age' apply 'func' error: func error", f.Err().Error())
+
+	is.Panics(func() {
+		f.AddRule("age", "trim").AddFunc(nil)
+	})
+}
+
+func TestFiltration_FilteringContext(t *testing.T) {
+	is := assert.New(t)
+	type ctxKey struct{}
+
+	f := New(map[string]any{"name": " hi ", "age": " 23 "})
+	f.AddFilter("greet", func(ctx context.Context, val any, args []string) (any, error) {
+		return ctx.Value(ctxKey{}).(string) + " " + val.(string), nil
+	})
+	f.AddRule("name", "trim|greet|upper")
+	f.AddRule("age", "trim|int")
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "inhere")
+	is.NoErr(f.FilteringContext(ctx))
+	is.Eq("INHERE HI", f.SafeVal("name"))
+	is.Eq(23, f.SafeVal("age"))
+
+	// cancel in the filter chain
+	ctx, cancel := context.WithCancel(context.Background())
+	f = New(map[string]any{"name": " hi ", "age": " 23 "}).WithOptions(CollectErrors)
+	f.AddFilter("cancel", func(ctx context.Context, val any, args []string) (any, error) {
+		cancel()
+		return val, nil
+	})
+	f.AddRule("name", "trim|cancel|upper")
+	f.AddRule("age", "trim|int")
+
+	err := f.FilteringContext(ctx)
+	is.Err(err)
+	is.True(errors.Is(err, context.Canceled))
+	is.Len(f.Errors(), 1)
+	is.Eq("upper", f.Errors()[0].Filter)
+	is.Eq("hi", f.Errors()[0].Value)
+	_, ok := f.Safe("age")
+	is.False(ok)
+
+	// ctx is done before filtering
+	f = New(map[string]any{"age": " 23 "})
+	f.AddRule("age", "trim|int")
+	err = f.FilteringContext(ctx)
+	is.True(errors.Is(err, context.Canceled))
+	is.ErrMsg(err, "filter: field 'age' apply 'context' error: context canceled")
+
+	// ctx is done between the rules
+	ctx, cancel = context.WithCancel(context.Background())
+	f = New(map[string]any{"name": " hi ", "age": " 23 ", "size": "1"}).WithOptions(CollectErrors)
+	f.AddFilter("cancel", func(ctx context.Context, val any, args []string) (any, error) {
+		cancel()
+		return val, nil
+	})
+	f.AddRule("name", "trim|cancel")
+	f.AddRule("age,size", "trim|int")
+
+	err = f.FilteringContext(ctx)
+	is.True(errors.Is(err, context.Canceled))
+	is.Eq("hi", f.SafeVal("name"))
+	is.Len(f.Errors(), 2)
+	is.Eq("context", f.Errors()[0].Filter)
+	is.Eq(map[string][]string{
+		"age":  {"context canceled"},
+		"size": {"context canceled"},
+	}, f.Errors().All())
+}
+
+func TestFiltration_crossField(t *testing.T) {
+	is := assert.New(t)
+
+	f := New(map[string]any{
+		"first":    " tom ",
+		"last":     " cat ",
+		"email":    " Tom@Example.com ",
+		"username": "",
+		"price":    "12.5",
+		"currency": "cents",
+	})
+	// cross-field rules are added before the depended rules
+	f.AddRule("full_name", func(val any, data DataView) (any, error) {
+		return data.String("first") + " " + data.String("last"), nil
+	}).AddFilters("upper")
+	f.AddRule("username", "trim").AddFieldFunc(func(val any, data DataView) (any, error) {
+		if val != "" {
+			return val, nil
+		}
+		return data.String("email"), nil
+	})
+	f.AddRule("price", "float").AddFieldFunc(func(val any, data DataView) (any, error) {
+		if data.String("currency") == "cents" {
+			return val.(float64) * 100, nil
+		}
+		return val, nil
+	})
+	f.AddRule("first,last", "trim")
+	f.AddRule("email", "trim|lower")
+	f.AddRule("currency", "trim|lower")
+	is.NoErr(f.Filtering())
+
+	is.Eq("TOM CAT", f.SafeVal("full_name"))
+	is.Eq("tom@example.com", f.SafeVal("username"))
+	is.Eq(float64(1250), f.SafeVal("price"))
+
+	// the field is missing, the filters before the func are skipped
+	f = New(map[string]any{"email": " Tom@Example.com ", "first": " tom "})
+	f.AddRule("username", "trim").AddFieldFunc(func(val any, data DataView) (any, error) {
+		if s, _ := val.(string); s != "" {
+			return s, nil
+		}
+		return data.String("email"), nil
+	}).AddFilters("upper")
+	f.AddRule("nick", "trim").AddFieldFunc(func(val any, data DataView) (any, error) {
+		is.Nil(val)
+		// the field without rule only can be read by Get()
+		is.Eq("", data.String("first"))
+		first, _ := data.Get("first")
+		return first, nil
+	})
+	f.AddRule("email", "trim|lower")
+	is.NoErr(f.Filtering())
+	is.Eq("TOM@EXAMPLE.COM", f.SafeVal("username"))
+	is.Eq(" tom ", f.SafeVal("nick"))
+
+	// the raw value can also be read
+	f = New(map[string]any{"name": " inhere "})
+	f.AddRule("name", "trim")
+	f.AddRule("raw_name", func(val any, data DataView) (any, error) {
+		raw, _ := data.Raw("name")
+		return raw, nil
+	})
+	is.NoErr(f.Filtering())
+	is.Eq(" inhere ", f.SafeVal("raw_name"))
+
+	// error
+	f = New(map[string]any{"name": "inhere"})
+	f.AddRule("name", FieldFunc(func(val any, data DataView) (any, error) {
+		return nil, errors.New("cross error")
+	}))
+	is.Err(f.Filtering())
+	is.Eq("filter: field 'name' apply 'func' error: cross error", f.Err().Error())
+
+	is.Panics(func() {
+		f.AddRule("name", "trim").AddFieldFunc(nil)
+	})
+}
+
+func TestFiltration_ruleOrder(t *testing.T) {
+	is := assert.New(t)
+
+	// AddRules with sorted fields
+	f := New(map[string]any{"a": " A ", "b": " B ", "c": " C "})
+	f.AddRules(map[string]string{"c": "trim", "a": "trim", "b": "trim"})
+	is.Eq([]string{"a"}, f.filterRules[0].Fields())
+	is.Eq([]string{"c"}, f.filterRules[2].Fields())
+
+	// AddRuleList in the given order. the second rule reads the filtered value
+	f = New(map[string]any{"name": " Inhere "})
+	f.AddRuleList(
+		FieldRule{Field: "name", Rule: "trim"},
+		FieldRule{Field: "name", Rule: "upper|substr:0,3"},
+	)
+	is.NoErr(f.Filtering())
+	is.Eq("INH", f.SafeVal("name"))
+
+	// priority
+	f = New(map[string]any{"name": " Inhere "})
+	f.AddRule("name", "upper|substr:0,3")
+	f.AddRule("name", "trim").Priority(10)
+	is.NoErr(f.Filtering())
+	is.Eq("INH", f.SafeVal("name"))
+
+	// after
+	f = New(map[string]any{"title": " Hello World ", "slug": ""})
+	f.AddRule("slug", func(val any, data DataView) (any, error) {
+		return data.String("title"), nil
+	}).AddFilters("upper").Priority(10)
+	f.AddRule("tag", "upper").After("title").Priority(5)
+	f.AddRule("title", "trim").Priority(-1)
+	order, err := f.ruleOrder()
+	is.NoErr(err)
+	is.Eq([]int{2, 1, 0}, order)
+	is.NoErr(f.Filtering())
+	is.Eq("HELLO WORLD", f.SafeVal("slug"))
+
+	// normal rule after a cross-field rule
+	f = New(map[string]any{"email": " Tom@Example.com "})
+	f.AddRule("username", "upper").After("email_copy")
+	f.AddRule("email_copy", func(val any, data DataView) (any, error) {
+		return data.String("email"), nil
+	})
+	f.AddRule("email", "trim|lower")
+	order, err = f.ruleOrder()
+	is.NoErr(err)
+	is.Eq([]int{2, 1, 0}, order)
+
+	// cycle
+	f = New(map[string]any{"a": "a", "b": "b"})
+	f.AddRule("a", "trim").After("b")
+	f.AddRule("b", "trim").After("a")
+	f.AddRule("c", "trim")
+	err = f.Filtering()
+	is.True(errors.Is(err, ErrRuleCycle))
+	is.ErrMsg(err, "filter: rule dependencies has cycle, the unresolved rule fields: a; b")
+	is.Eq(err, f.Filtering())
+}
+
+func TestFiltration_typedGetters(t *testing.T) {
+	is := assert.New(t)
+
+	f := New(map[string]any{
+		"ok":       "yes",
+		"bad_bool": "abc",
+		"age":      " 23 ",
+		"price":    " 12.5 ",
+		"time":     "2018-10-16 12:34:01",
+		"ttl":      "1h30m",
+		"tags":     "a, b",
+		"ids":      []string{"1", "2"},
+		"meta":     map[string]string{"k": "v"},
+		"big":      "300",
+	})
+	f.AddRule("ok,bad_bool,age,price,time,ttl,big", "trim")
+	f.AddRule("tags", "str2arr")
+	f.AddRule("ids,meta", func(val any) (any, error) {
+		return val, nil
+	})
+	is.NoErr(f.Filtering())
+
+	// Bool not panic on not bool value
+	is.True(f.Bool("ok"))
+	is.False(f.Bool("bad_bool"))
+	is.False(f.Bool("not-exist"))
+
+	is.Eq(uint(23), f.Uint("age"))
+	is.Eq(12.5, f.Float("price"))
+	is.Eq(2018, f.Time("time").Year())
+	is.Eq(90*time.Minute, f.Duration("ttl"))
+	is.Eq([]string{"a", "b"}, f.Strings("tags"))
+	is.Eq([]int{1, 2}, f.Ints("ids"))
+	is.Eq(map[string]any{"k": "v"}, f.Map("meta"))
+	is.Nil(f.Map("not-exist"))
+
+	// GetAs
+	age, err := GetAs[int](f, "age")
+	is.NoErr(err)
+	is.Eq(23, age)
+	str, err := GetAs[string](f, "price")
+	is.NoErr(err)
+	is.Eq("12.5", str)
+
+	_, err = GetAs[int](f, "not-exist")
+	is.True(errors.Is(err, ErrKeyNotFound))
+	is.ErrMsg(err, "filter: get 'not-exist' error: key not found")
+	_, err = GetAs[bool](f, "bad_bool")
+	is.True(errors.Is(err, ErrTypeMismatch))
+	_, err = GetAs[map[string]int](f, "meta")
+	is.True(errors.Is(err, ErrTypeMismatch))
+	_, err = GetAs[int8](f, "big")
+	is.True(errors.Is(err, ErrTypeMismatch))
+	is.StrContains(err.Error(), "cannot convert string to int8: value out of range")
+
+	is.Eq(23, MustGetAs[int](f, "age"))
+	is.Panics(func() {
+		MustGetAs[int](f, "tags")
+	})
+}
+
+func TestFiltration_MergedData(t *testing.T) {
+	is := assert.New(t)
+
+	raw := map[string]any{
+		"tags":  []any{"a", "b", "a"},
+		"items": []any{map[string]any{"name": " a ", "id": 1}},
+		"user":  map[string]any{"name": " tom ", "age": 23},
+	}
+	f := New(raw)
+	f.AddRule("tags", "unique")
+	f.AddRule("items.*.name,user.name", "trim")
+	is.NoErr(f.Filtering())
+
+	is.Eq(map[string]any{
+		"tags":  []any{"a", "b"},
+		"items": []any{map[string]any{"name": "a", "id": 1}},
+		"user":  map[string]any{"name": "tom", "age": 23},
+	}, f.MergedData())
+	// raw data not changed
+	is.Eq(" tom ", raw["user"].(map[string]any)["name"])
+}
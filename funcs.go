@@ -0,0 +1,180 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// funcFilter is a typed filter func wrapper, will call it by reflection.
+//
+// The first param of the func is the filtering value, other params are
+// the filter args. the func should return one value or (value, error).
+// the func can accept a context.Context as the first param.
+//
+// eg:
+//
+//	func(s string, n int) string
+//	func(v []int, desc bool) ([]int, error)
+//	func(ctx context.Context, s string) (string, error)
+type funcFilter struct {
+	name string
+	fv   reflect.Value
+	ft   reflect.Type
+	// number of required args, not contains the context and value param
+	reqNum int
+	// the func last return is error
+	hasErr bool
+	// the func first param is context.Context
+	hasCtx bool
+}
+
+// toFilterFunc convert a func to ContextFilterFunc. will panic on invalid func.
+func toFilterFunc(name string, fn any) ContextFilterFunc {
+	cfn, _ := newFilter(name, fn)
+	return cfn
+}
+
+// newFilter convert a func to ContextFilterFunc, the *funcFilter is
+// not nil on the fn is a typed func. will panic on invalid func.
+func newFilter(name string, fn any) (ContextFilterFunc, *funcFilter) {
+	switch tfn := fn.(type) {
+	case ContextFilterFunc:
+		return tfn, nil
+	case func(context.Context, any, []string) (any, error):
+		return tfn, nil
+	case FilterFunc:
+		return wrapFilterFunc(tfn), nil
+	case func(any, []string) (any, error):
+		return wrapFilterFunc(tfn), nil
+	}
+
+	ff := newFuncFilter(name, fn)
+	return ff.Call, ff
+}
+
+// wrap the FilterFunc to ContextFilterFunc
+func wrapFilterFunc(fn FilterFunc) ContextFilterFunc {
+	return func(_ context.Context, val any, args []string) (any, error) {
+		return fn(val, args)
+	}
+}
+
+func newFuncFilter(name string, fn any) *funcFilter {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		panic(fmt.Sprintf("filter: the filter '%s' must be a func, but got %T", name, fn))
+	}
+
+	ft := fv.Type()
+	hasCtx := ft.NumIn() > 0 && ft.In(0) == contextType
+
+	minIn := 1
+	if hasCtx {
+		minIn = 2
+	}
+	if ft.NumIn() < minIn {
+		panic(fmt.Sprintf("filter: the filter '%s' func must have at least one param", name))
+	}
+
+	numOut := ft.NumOut()
+	if numOut == 0 || numOut > 2 || (numOut == 2 && ft.Out(1) != errorType) {
+		panic(fmt.Sprintf("filter: the filter '%s' func must return (value) or (value, error)", name))
+	}
+
+	reqNum := ft.NumIn() - minIn
+	if ft.IsVariadic() {
+		reqNum--
+	}
+
+	return &funcFilter{
+		name:   name,
+		fv:     fv,
+		ft:     ft,
+		reqNum: reqNum,
+		hasErr: numOut == 2,
+		hasCtx: hasCtx,
+	}
+}
+
+// Call the func by reflection. will convert val and args to the func param types.
+func (ff *funcFilter) Call(ctx context.Context, val any, args []string) (any, error) {
+	if err := ff.checkArgNum(args); err != nil {
+		return nil, err
+	}
+
+	// offset of the value param
+	var vi int
+	in := make([]reflect.Value, 0, len(args)+2)
+	if ff.hasCtx {
+		vi = 1
+		in = append(in, reflect.ValueOf(&ctx).Elem())
+	}
+
+	rv, err := convType(val, ff.ft.In(vi))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTypeMismatch, err.Error())
+	}
+
+	avs, err := ff.convArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	in = append(append(in, rv), avs...)
+
+	out := ff.fv.Call(in)
+	if ff.hasErr && !out[1].IsNil() {
+		return nil, out[1].Interface().(error)
+	}
+	return out[0].Interface(), nil
+}
+
+// CheckArgs check the args number and convert them to the param types.
+func (ff *funcFilter) CheckArgs(args []string) error {
+	if err := ff.checkArgNum(args); err != nil {
+		return err
+	}
+
+	_, err := ff.convArgs(args)
+	return err
+}
+
+func (ff *funcFilter) checkArgNum(args []string) error {
+	argNum := len(args)
+	if argNum < ff.reqNum || (!ff.ft.IsVariadic() && argNum > ff.reqNum) {
+		return fmt.Errorf("%w: expect %d args, but given %d", ErrBadArgs, ff.reqNum, argNum)
+	}
+	return nil
+}
+
+// convert the args to the param types
+func (ff *funcFilter) convArgs(args []string) ([]reflect.Value, error) {
+	// offset of the value param
+	var vi int
+	if ff.hasCtx {
+		vi = 1
+	}
+
+	lastIdx := ff.ft.NumIn() - 1
+	avs := make([]reflect.Value, 0, len(args))
+	for i, arg := range args {
+		var typ reflect.Type
+		if ff.ft.IsVariadic() && vi+i+1 >= lastIdx {
+			typ = ff.ft.In(lastIdx).Elem()
+		} else {
+			typ = ff.ft.In(vi + i + 1)
+		}
+
+		av, err := convType(arg, typ)
+		if err != nil {
+			return nil, fmt.Errorf("%w: arg #%d %s", ErrBadArgs, i, err.Error())
+		}
+		avs = append(avs, av)
+	}
+	return avs, nil
+}
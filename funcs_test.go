@@ -0,0 +1,127 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestToFilterFunc(t *testing.T) {
+	is := assert.New(t)
+	ctx := context.Background()
+
+	fn := toFilterFunc("repeat", func(s string, n int) string {
+		return strings.Repeat(s, n)
+	})
+	ret, err := fn(ctx, "ab", []string{"2"})
+	is.NoErr(err)
+	is.Eq("abab", ret)
+
+	// pointer string value
+	str := "ab"
+	ret, err = fn(ctx, &str, []string{" 3 "})
+	is.NoErr(err)
+	is.Eq("ababab", ret)
+
+	// bad args
+	_, err = fn(ctx, "ab", nil)
+	is.ErrMsg(err, "invalid filter args: expect 1 args, but given 0")
+	is.True(errors.Is(err, ErrBadArgs))
+	_, err = fn(ctx, "ab", []string{"1", "2"})
+	is.ErrMsg(err, "invalid filter args: expect 1 args, but given 2")
+	_, err = fn(ctx, "ab", []string{"two"})
+	is.True(errors.Is(err, ErrBadArgs))
+	is.StrContains(err.Error(), "arg #0 cannot convert string to int")
+	_, err = fn(ctx, []int{1}, []string{"2"})
+	is.True(errors.Is(err, ErrTypeMismatch))
+
+	// out of range number
+	fn = toFilterFunc("shift", func(n int64, m int8, f float32) float64 {
+		return float64(n) + float64(m) + float64(f)
+	})
+	ret, err = fn(ctx, "1", []string{"127", "1.5"})
+	is.NoErr(err)
+	is.Eq(129.5, ret)
+	_, err = fn(ctx, "1", []string{"300", "1"})
+	is.True(errors.Is(err, ErrBadArgs))
+	is.ErrMsg(err, "invalid filter args: arg #0 cannot convert string to int8: value out of range")
+	_, err = fn(ctx, "1", []string{"1", "1e40"})
+	is.True(errors.Is(err, ErrBadArgs))
+	is.StrContains(err.Error(), "arg #1 cannot convert string to float32: value out of range")
+
+	// return error, convert slice value
+	fn = toFilterFunc("sortInts", func(v []int, desc bool) ([]int, error) {
+		if desc {
+			sort.Sort(sort.Reverse(sort.IntSlice(v)))
+		} else {
+			sort.Ints(v)
+		}
+		return v, nil
+	})
+	ret, err = fn(ctx, []string{"3", "1", "2"}, []string{"true"})
+	is.NoErr(err)
+	is.Eq([]int{3, 2, 1}, ret)
+	ret, err = fn(ctx, "3,1,2", []string{"false"})
+	is.NoErr(err)
+	is.Eq([]int{1, 2, 3}, ret)
+
+	// variadic args
+	fn = toFilterFunc("join", func(s string, ss ...string) string {
+		return s + strings.Join(ss, "")
+	})
+	ret, err = fn(ctx, "a", []string{"b", "c"})
+	is.NoErr(err)
+	is.Eq("abc", ret)
+	ret, err = fn(ctx, "a", nil)
+	is.NoErr(err)
+	is.Eq("a", ret)
+
+	// time and duration
+	fn = toFilterFunc("addTime", func(t time.Time, d time.Duration) time.Time {
+		return t.Add(d)
+	})
+	ret, err = fn(ctx, "2018-10-16 12:34", []string{"1h"})
+	is.NoErr(err)
+	is.Eq(13, ret.(time.Time).Hour())
+
+	is.Panics(func() {
+		toFilterFunc("invalid", "not func")
+	})
+	is.Panics(func() {
+		toFilterFunc("invalid", func() string { return "" })
+	})
+	is.Panics(func() {
+		toFilterFunc("invalid", func(s string) (string, string) { return "", "" })
+	})
+	is.Panics(func() {
+		toFilterFunc("invalid", func(ctx context.Context) string { return "" })
+	})
+
+	// with context param
+	type ctxKey struct{}
+	fn = toFilterFunc("prefix", func(ctx context.Context, s string, n int) string {
+		return strings.Repeat(ctx.Value(ctxKey{}).(string), n) + s
+	})
+	ret, err = fn(context.WithValue(ctx, ctxKey{}, "-"), "ab", []string{"2"})
+	is.NoErr(err)
+	is.Eq("--ab", ret)
+
+	// FilterFunc and ContextFilterFunc
+	fn = toFilterFunc("upper", FilterFunc(func(val any, args []string) (any, error) {
+		return strings.ToUpper(val.(string)), nil
+	}))
+	ret, err = fn(ctx, "ab", nil)
+	is.NoErr(err)
+	is.Eq("AB", ret)
+	fn = toFilterFunc("ctxVal", func(ctx context.Context, val any, args []string) (any, error) {
+		return ctx.Value(ctxKey{}), nil
+	})
+	ret, err = fn(context.WithValue(ctx, ctxKey{}, "val"), "ab", nil)
+	is.NoErr(err)
+	is.Eq("val", ret)
+}
@@ -0,0 +1,260 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gookit/goutil/strutil"
+)
+
+// schemaEntry a field entry of the schema document
+type schemaEntry struct {
+	// field path(s), multi fields split by ','
+	field string
+	// rule strings
+	filters  []string
+	defVal   any
+	required bool
+	// condition with args. eg: "fieldEq:type,numeric"
+	when string
+}
+
+// LoadSchema load the rules from a JSON or YAML document to a Schema.
+//
+// The document maps the field paths to the rule strings or the entries.
+// entry keys:
+//
+//   - filters: the rule string or a list of rule strings
+//   - default: the default value, see Rule.SetDefaultVal(). it will be filtered by the filters too
+//   - required: the field cannot be missing or empty, see Rule.Required()
+//   - when: a condition with args, the rule only be applied on it is true. see AddCondition()
+//
+// JSON example:
+//
+//	{
+//	  "name": "trim|ucFirst",
+//	  "age": {"filters": "trim|int", "default": "18"},
+//	  "email": {"filters": ["trim", "email"], "required": true},
+//	  "value": {"filters": "int", "when": "fieldEq:type,numeric"}
+//	}
+//
+// YAML example:
+//
+//	name: trim|ucFirst
+//	age:
+//	  filters: trim|int
+//	  default: "18"
+//	tags:
+//	  filters:
+//	    - trim
+//	    - "str2arr:,"
+//
+// The document is parsed as JSON on it starts with '{', otherwise is
+// parsed as a simple YAML subset: mappings, lists of scalars and comments.
+//
+// The filter names, condition names and filter args are checked on load.
+// the rules will be applied in the document order.
+func LoadSchema(r io.Reader, optFns ...func(opt *Options)) (*Schema, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	var data map[string]any
+	if src = bytes.TrimSpace(src); len(src) > 0 && src[0] == '{' {
+		fields, data, err = parseJSONSchema(src)
+	} else {
+		fields, data, err = parseYAML(string(src))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := newSchema(optFns)
+	for _, field := range fields {
+		e, err := newSchemaEntry(field, data[field])
+		if err == nil {
+			err = s.addEntry(e)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w, at the schema field '%s'", err, field)
+		}
+	}
+	return s, nil
+}
+
+// parseJSONSchema parse the JSON object, returns the keys in the document order.
+func parseJSONSchema(src []byte) (keys []string, data map[string]any, err error) {
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+
+	// the '{' has been checked
+	if _, err = dec.Token(); err != nil {
+		return nil, nil, fmt.Errorf("filter: decode JSON schema error: %w", err)
+	}
+
+	data = make(map[string]any)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, fmt.Errorf("filter: decode JSON schema error: %w", err)
+		}
+
+		key := tok.(string)
+		if _, ok := data[key]; ok {
+			return nil, nil, fmt.Errorf("filter: decode JSON schema error: duplicate key '%s'", key)
+		}
+
+		var val any
+		if err = dec.Decode(&val); err != nil {
+			return nil, nil, fmt.Errorf("filter: decode JSON schema error: %w", err)
+		}
+		keys = append(keys, key)
+		data[key] = jsonNumber(val)
+	}
+
+	// the end '}' and must be EOF after it
+	if _, err = dec.Token(); err == nil {
+		if _, err = dec.Token(); err == nil {
+			err = errors.New("unexpected data after the object")
+		} else if err == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("filter: decode JSON schema error: %w", err)
+	}
+	return keys, data, nil
+}
+
+// newSchemaEntry create the entry from the value of the field.
+// the value can be a rule string or an entry map.
+func newSchemaEntry(field string, val any) (*schemaEntry, error) {
+	e := &schemaEntry{field: field}
+	switch tv := val.(type) {
+	case string:
+		e.filters = []string{tv}
+		return e, nil
+	case map[string]any:
+		// sort keys for the stable error
+		keys := make([]string, 0, len(tv))
+		for key := range tv {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if err := e.set(key, tv[key]); err != nil {
+				return nil, err
+			}
+		}
+		return e, nil
+	}
+	return nil, fmt.Errorf("filter: the schema entry must be a rule string or an object, but got %T", val)
+}
+
+func (e *schemaEntry) set(key string, val any) error {
+	var ok bool
+	switch key {
+	case "filters":
+		switch tv := val.(type) {
+		case string:
+			e.filters, ok = []string{tv}, true
+		case []any:
+			ok = true
+			e.filters = make([]string, len(tv))
+			for i, v := range tv {
+				if e.filters[i], ok = v.(string); !ok {
+					break
+				}
+			}
+		}
+	case "default":
+		e.defVal, ok = val, true
+	case "required":
+		e.required, ok = val.(bool)
+	case "when":
+		e.when, ok = val.(string)
+	default:
+		return fmt.Errorf("filter: unknown schema entry key '%s'", key)
+	}
+
+	if !ok {
+		return fmt.Errorf("filter: invalid value type %T of the schema entry key '%s'", val, key)
+	}
+	return nil
+}
+
+// addEntry add a rule by the schema entry
+func (s *Schema) addEntry(e *schemaEntry) error {
+	fields := strutil.Split(e.field, ",")
+	if len(fields) == 0 {
+		return errEmptyFields
+	}
+
+	r := newRule(fields)
+	r.checkFn = s.checkFilter
+	for _, rule := range e.filters {
+		steps, err := parseRule(rule)
+		if err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			return errors.New("filter: invalid 'rule' params, cannot be empty")
+		}
+
+		if err = r.addSteps(steps); err != nil {
+			return err
+		}
+	}
+
+	if len(r.steps) == 0 && e.defVal == nil && !e.required {
+		return errors.New("filter: the schema entry must have the filters, default or required")
+	}
+
+	if e.defVal != nil {
+		r.SetDefaultVal(e.defVal)
+	}
+	if e.required {
+		r.Required()
+	}
+	if e.when != "" {
+		fn, err := parseWhen(e.when)
+		if err != nil {
+			return err
+		}
+		r.When(fn)
+	}
+
+	// pre-resolve filter funcs
+	resolveSteps(r.steps, resolveFilter)
+
+	s.rules = append(s.rules, r)
+	return nil
+}
+
+// parseWhen parse the condition string to the rule condition func. eg: "fieldEq:type,numeric"
+func parseWhen(when string) (func(val any, f *Filtration) bool, error) {
+	steps, err := parseRule(when)
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) != 1 || steps[0].cond != "" {
+		return nil, fmt.Errorf("filter: invalid condition %q, expect 'name[:args]'", when)
+	}
+
+	name, args := steps[0].name, steps[0].args
+	if err = checkCond(name); err != nil {
+		return nil, fmt.Errorf("filter: %w", err)
+	}
+
+	cond := conditions[name]
+	return func(val any, f *Filtration) bool {
+		return cond(val, args, f)
+	}, nil
+}
@@ -0,0 +1,179 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestLoadSchema_json(t *testing.T) {
+	is := assert.New(t)
+
+	s, err := LoadSchema(strings.NewReader(`{
+  "name": "trim|ucFirst",
+  "age": {"filters": "int", "default": 18},
+  "tags": {"filters": ["trim", "str2arr:,"]},
+  "email": {"filters": ["trim", "email"], "required": true},
+  "value": {"filters": "int", "when": "fieldEq:type,numeric"}
+}`))
+	is.NoErr(err)
+	is.Len(s.rules, 5)
+	is.Eq([]string{"name"}, s.rules[0].Fields())
+	is.Eq([]string{"value"}, s.rules[4].Fields())
+
+	data, err := s.Apply(map[string]any{
+		"name":  " inhere ",
+		"tags":  " a,b ",
+		"email": " a@b.com ",
+		"type":  "numeric",
+		"value": "23",
+	})
+	is.NoErr(err)
+	is.Eq("Inhere", data["name"])
+	is.Eq(18, data["age"])
+	is.Eq([]string{"a", "b"}, data["tags"])
+	is.Eq("a@b.com", data["email"])
+	is.Eq(23, data["value"])
+
+	// when is false
+	data, err = s.Apply(map[string]any{"email": "a@b.com", "type": "text", "value": "abc"})
+	is.NoErr(err)
+	is.NotContains(data, "value")
+
+	// required
+	_, err = s.Apply(map[string]any{"name": "tom"})
+	is.True(errors.Is(err, ErrRequired))
+	is.ErrMsg(err, "filter: field 'email' apply 'required' error: value is required")
+	_, err = s.Apply(map[string]any{"email": ""})
+	is.True(errors.Is(err, ErrRequired))
+
+	// default only
+	s, err = LoadSchema(strings.NewReader(`{"role": {"default": "guest"}, "rate": {"default": 1.5}}`))
+	is.NoErr(err)
+	data, err = s.Apply(map[string]any{})
+	is.NoErr(err)
+	is.Eq("guest", data["role"])
+	is.Eq(1.5, data["rate"])
+}
+
+func TestLoadSchema_yaml(t *testing.T) {
+	is := assert.New(t)
+
+	s, err := LoadSchema(strings.NewReader(`
+# user fields
+name: trim|ucFirst
+age:
+  filters: int
+  default: 18
+tags:
+  filters:
+    - trim
+    - "str2arr:,"
+"items.*.title":
+  filters: trim|upper
+  when: notEmpty
+email:
+  filters: trim|email # the email
+  required: true
+`))
+	is.NoErr(err)
+	is.Len(s.rules, 5)
+	is.Eq([]string{"items.*.title"}, s.rules[3].Fields())
+
+	data, err := s.Apply(map[string]any{
+		"name":  " inhere ",
+		"tags":  " a,b ",
+		"email": " a@b.com ",
+		"items": []any{map[string]any{"title": " a "}, map[string]any{"title": ""}},
+	})
+	is.NoErr(err)
+	is.Eq("Inhere", data["name"])
+	is.Eq(18, data["age"])
+	is.Eq([]string{"a", "b"}, data["tags"])
+	is.Eq("a@b.com", data["email"])
+	is.Eq("A", data["items"].([]any)[0].(map[string]any)["title"])
+}
+
+// the examples of the LoadSchema() doc
+func TestLoadSchema_docExample(t *testing.T) {
+	is := assert.New(t)
+
+	docs := []string{`{
+  "name": "trim|ucFirst",
+  "age": {"filters": "trim|int", "default": "18"},
+  "email": {"filters": ["trim", "email"], "required": true},
+  "value": {"filters": "int", "when": "fieldEq:type,numeric"}
+}`, `
+name: trim|ucFirst
+age:
+  filters: trim|int
+  default: "18"
+tags:
+  filters:
+    - trim
+    - "str2arr:,"
+`}
+
+	for _, doc := range docs {
+		s, err := LoadSchema(strings.NewReader(doc))
+		is.NoErr(err)
+
+		data, err := s.Apply(map[string]any{"name": " tom ", "email": "a@b.com", "tags": "a,b"})
+		is.NoErr(err)
+		is.Eq("Tom", data["name"])
+		is.Eq(18, data["age"])
+	}
+
+	// the default value is filtered by the filters too
+	s, err := LoadSchema(strings.NewReader(`{"age": {"filters": "trim|int", "default": 18}}`))
+	is.NoErr(err)
+	_, err = s.Apply(map[string]any{})
+	is.ErrSubMsg(err, "only use for string type, input int")
+}
+
+func TestLoadSchema_error(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		doc string
+		err string
+	}{
+		{`{"name": "trm"}`, "filter: unknown filter 'trm', did you mean 'trim'?, at the schema field 'name'"},
+		{`{"name": "substr:1"}`, "filter: check 'substr' error: invalid filter args: expect 2 args, but given 1, at the schema field 'name'"},
+		{`{"name": "substr:a,b"}`, "at the schema field 'name'"},
+		{`{"name": "int:10"}`, "filter: check 'int' error: invalid filter args: expect 0 args, but given 1"},
+		{`{"name": "snake:a,b"}`, "expect 0 to 1 args, but given 2"},
+		{`{"name": "if:unknown(trim)"}`, "filter: unknown filter condition 'unknown'"},
+		{`{"name": ""}`, "cannot be empty"},
+		{`{"name": 12}`, "must be a rule string or an object, but got int"},
+		{`{"name": {}}`, "must have the filters, default or required"},
+		{`{"name": {"filter": "trim"}}`, "unknown schema entry key 'filter'"},
+		{`{"name": {"filters": [1]}}`, "invalid value type []interface {} of the schema entry key 'filters'"},
+		{`{"name": {"filters": "trim", "required": "yes"}}`, "schema entry key 'required'"},
+		{`{"name": {"filters": "trim", "when": "notExists"}}`, "unknown filter condition 'notExists'"},
+		{`{"name": {"filters": "trim", "when": "notEmpty|empty"}}`, "invalid condition \"notEmpty|empty\""},
+		{`{"name": "trim", "name": "int"}`, "duplicate key 'name'"},
+		{`{"name": "trim"} {}`, "unexpected data after the object"},
+		{`{"name": "trim"`, "filter: decode JSON schema error"},
+		{"name: trm", "did you mean 'trim'?, at the schema field 'name'"},
+		{"name", "filter: parse YAML error at line 1: expect 'key: value'"},
+	}
+
+	for _, tt := range tests {
+		_, err := LoadSchema(strings.NewReader(tt.doc))
+		is.ErrSubMsg(err, tt.err)
+	}
+
+	// typed custom filter
+	AddFilter("repeatStr", strings.Repeat)
+	_, err := LoadSchema(strings.NewReader(`{"name": "repeatStr:2"}`))
+	is.NoErr(err)
+	_, err = LoadSchema(strings.NewReader(`{"name": "repeatStr:a"}`))
+	is.ErrSubMsg(err, "filter: check 'repeatStr' error: invalid filter args: arg #0")
+
+	// lenient mode
+	_, err = LoadSchema(strings.NewReader(`{"name": "trm:a,b"}`), LenientMode)
+	is.NoErr(err)
+}
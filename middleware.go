@@ -0,0 +1,236 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+)
+
+// MiddlewareOptions for the Middleware
+type MiddlewareOptions struct {
+	// ContextOnly only store the Filtration in the request context, dont
+	// replace the request values with the filtered values. default is false
+	ContextOnly bool
+	// ErrorStatus the response status code on filtering failed. default is 400
+	ErrorStatus int
+	// ErrorPayload build the JSON payload for the error response. default is ErrorPayload()
+	ErrorPayload func(status int, err error) any
+	// ErrorHandler custom handle the error on read request or filtering failed.
+	// if is set, the ErrorStatus and ErrorPayload will be ignored.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// context key for store the Filtration
+type filtrationKey struct{}
+
+// Middleware create a net/http middleware, it will filter the query params,
+// form fields and JSON body of the request by the schema.
+//
+// On success, the request values will be replaced with the filtered values,
+// and the Filtration will be stored in the request context, see FromContext().
+// On failure, will respond the error JSON payload with status 400.
+//
+// Replace the request values:
+//
+//   - query params: r.URL.RawQuery and r.Form
+//   - form fields: r.PostForm, r.Form and r.MultipartForm.Value
+//   - JSON body: r.Body will be re-encoded JSON
+//
+// The query params and the body values are replaced separately, the query
+// param that overridden by the body value is filtered by a query only Filtration.
+// The nested maps are encoded in the bracket notation(eg: "user[name]"),
+// the slices are encoded as the repeated keys, the original key spelling
+// will be kept. eg: "tags[]"
+//
+// Usage:
+//
+//	s := filter.MustCompile(map[string]string{"name": "trim", "age": "int"})
+//	http.Handle("/users", filter.Middleware(s)(handler))
+//
+//	// in handler
+//	f := filter.FromContext(r.Context())
+//	age := f.Int("age")
+func Middleware(s *Schema, optFns ...func(opt *MiddlewareOptions)) func(http.Handler) http.Handler {
+	opts := &MiddlewareOptions{
+		ErrorStatus:  http.StatusBadRequest,
+		ErrorPayload: ErrorPayload,
+	}
+	for _, fn := range optFns {
+		fn(opts)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query, body, err := readRequest(r)
+			if err != nil {
+				opts.handleError(w, r, err)
+				return
+			}
+
+			data := make(map[string]any, len(query)+len(body))
+			for key, val := range query {
+				data[key] = val
+			}
+			for key, val := range body {
+				data[key] = val
+			}
+
+			f := s.New(data)
+			if err = f.FilteringContext(r.Context()); err != nil {
+				opts.handleError(w, r, err)
+				return
+			}
+
+			if !opts.ContextOnly {
+				data = f.MergedData()
+				replaceRequest(r, query, body, data, queryData(r.Context(), s, query, body, data))
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), filtrationKey{}, f)))
+		})
+	}
+}
+
+// FromContext get the Filtration stored by the Middleware. returns nil on not found.
+func FromContext(ctx context.Context) *Filtration {
+	f, _ := ctx.Value(filtrationKey{}).(*Filtration)
+	return f
+}
+
+// ErrorPayload the default error payload for the Middleware. eg:
+//
+//	{"code": 400, "message": "...", "errors": {"age": ["..."]}}
+func ErrorPayload(status int, err error) any {
+	payload := map[string]any{
+		"code":    status,
+		"message": err.Error(),
+	}
+
+	var es Errors
+	var fe *FilterError
+	if errors.As(err, &es) {
+		payload["errors"] = es.All()
+	} else if errors.As(err, &fe) {
+		payload["errors"] = Errors{fe}.All()
+	}
+	return payload
+}
+
+func (opts *MiddlewareOptions) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if opts.ErrorHandler != nil {
+		opts.ErrorHandler(w, r, err)
+		return
+	}
+
+	status := opts.ErrorStatus
+	if errors.Is(err, ErrBodyTooLarge) {
+		status = http.StatusRequestEntityTooLarge
+	}
+
+	payload := opts.ErrorPayload(status, err)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// queryData get the filtered query data. the query params that overridden by
+// the body values will be filtered by a query only Filtration, the failed
+// values are kept as is.
+func queryData(ctx context.Context, s *Schema, query, body, data map[string]any) map[string]any {
+	for key := range query {
+		if _, ok := body[key]; ok {
+			qf := s.New(query).WithOptions(CollectErrors)
+			_ = qf.FilteringContext(ctx)
+			return qf.MergedData()
+		}
+	}
+	return data
+}
+
+// replace the request values with the filtered data, the bdata for the body
+// values and the qdata for the query params.
+func replaceRequest(r *http.Request, query, body, bdata, qdata map[string]any) {
+	qv := make(url.Values, len(query))
+	for key := range query {
+		encodeValues(qv, key, qdata[key])
+	}
+	keepKeys(qv, r.URL.Query())
+	r.URL.RawQuery = qv.Encode()
+
+	form := make(url.Values, len(qv))
+	switch bodyType(r) {
+	case bodyJSON:
+		bm := make(map[string]any, len(body))
+		for key := range body {
+			bm[key] = bdata[key]
+		}
+
+		if bs, err := json.Marshal(bm); err == nil {
+			r.Body = io.NopCloser(bytes.NewReader(bs))
+			r.ContentLength = int64(len(bs))
+		}
+	case bodyForm, bodyMultipart:
+		pv := make(url.Values, len(body))
+		for key := range body {
+			encodeValues(pv, key, bdata[key])
+		}
+		keepKeys(pv, r.PostForm)
+
+		r.PostForm = pv
+		if r.MultipartForm != nil {
+			r.MultipartForm.Value = pv
+		}
+		// same as the net/http, the body values are first
+		for key, vs := range pv {
+			form[key] = append(form[key], vs...)
+		}
+	}
+
+	for key, vs := range qv {
+		form[key] = append(form[key], vs...)
+	}
+	r.Form = form
+}
+
+// keepKeys rename the encoded keys to the original spelling. eg: "tags" -> "tags[]"
+func keepKeys(vs, orig url.Values) {
+	for key, val := range vs {
+		if _, ok := orig[key]; ok {
+			continue
+		}
+		if _, ok := orig[key+"[]"]; ok {
+			delete(vs, key)
+			vs[key+"[]"] = val
+		}
+	}
+}
+
+// encodeValues encode the value to the url.Values by the key.
+func encodeValues(vs url.Values, key string, val any) {
+	switch tv := val.(type) {
+	case nil, *multipart.FileHeader, []*multipart.FileHeader:
+		// skip the files
+	case string:
+		vs.Add(key, tv)
+	case []string:
+		vs[key] = append(vs[key], tv...)
+	case map[string]any:
+		for k, v := range tv {
+			encodeValues(vs, key+"["+k+"]", v)
+		}
+	default:
+		if isSlice(val) {
+			rv := reflect.ValueOf(val)
+			for i := 0; i < rv.Len(); i++ {
+				vs.Add(key, MustString(rv.Index(i).Interface()))
+			}
+		} else {
+			vs.Add(key, MustString(val))
+		}
+	}
+}
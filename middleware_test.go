@@ -0,0 +1,195 @@
+package filter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	is := assert.New(t)
+
+	s := MustCompile(map[string]string{
+		"name":      "trim|ucFirst",
+		"age":       "trim|int",
+		"tags":      "trim|lower|unique",
+		"user.city": "trim|upper",
+	})
+
+	var f *Filtration
+	var body string
+	h := Middleware(s)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f = FromContext(r.Context())
+		bs, _ := io.ReadAll(r.Body)
+		body = string(bs)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	// query params
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?name=+inhere+&age=+23&tags=A&tags=a&tags=B&page=1", nil))
+	is.Eq(http.StatusNoContent, w.Code)
+	is.NotNil(f)
+	is.Eq("Inhere", f.String("name"))
+	is.Eq(23, f.Int("age"))
+
+	// replace the request values
+	var req *http.Request
+	h2 := Middleware(s)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+	}))
+	h2.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?name=+inhere+&age=+23&tags=A&tags=a&tags=B&page=1", nil))
+	is.Eq("Inhere", req.URL.Query().Get("name"))
+	is.Eq("23", req.FormValue("age"))
+	is.Eq([]string{"a", "b"}, req.Form["tags"])
+	is.Eq("1", req.FormValue("page"))
+
+	// form body
+	r := httptest.NewRequest(http.MethodPost, "/?page=1", strings.NewReader("name=+tom+&user[city]=+sz+&user[zip]=123"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h2.ServeHTTP(httptest.NewRecorder(), r)
+	is.Eq("Tom", req.PostFormValue("name"))
+	is.Eq("SZ", req.PostFormValue("user[city]"))
+	is.Eq("123", req.PostFormValue("user[zip]"))
+	is.Eq("1", req.FormValue("page"))
+	is.Eq("", req.PostFormValue("page"))
+
+	// JSON body
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": " tom ", "tags": [" A ", "a"], "user": {"city": " sz ", "zip": "123"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	is.Eq(http.StatusNoContent, w.Code)
+	is.Eq(`{"name":"Tom","tags":["a"],"user":{"city":"SZ","zip":"123"}}`, body)
+	is.Eq("SZ", f.String("user.city"))
+
+	// the large number is kept
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id": 12345678901234567890, "age": " 23"}`))
+	r.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	is.Eq(`{"age":23,"id":12345678901234567890}`, body)
+}
+
+func TestMiddleware_queryAndBody(t *testing.T) {
+	is := assert.New(t)
+
+	s := MustCompile(map[string]string{
+		"name": "trim|ucFirst",
+		"tags": "trim|lower|unique",
+	})
+
+	var req *http.Request
+	var body string
+	h := Middleware(s)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		bs, _ := io.ReadAll(r.Body)
+		body = string(bs)
+	}))
+
+	// the query value is not overridden by the body value
+	r := httptest.NewRequest(http.MethodPost, "/?name=+query+&page=1", strings.NewReader(`{"name": " body "}`))
+	r.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	is.Eq("Query", req.URL.Query().Get("name"))
+	is.Eq("1", req.URL.Query().Get("page"))
+	is.Eq(`{"name":"Body"}`, body)
+	is.Eq("Body", FromContext(req.Context()).String("name"))
+
+	// keep the original key spelling
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?tags[]=+A+&tags[]=a&name=+tom+", nil))
+	is.Eq([]string{"a"}, req.URL.Query()["tags[]"])
+	is.Eq([]string{"a"}, req.Form["tags[]"])
+	is.Nil(req.Form["tags"])
+	is.Eq("Tom", req.FormValue("name"))
+
+	r = httptest.NewRequest(http.MethodPost, "/?name=+query+", strings.NewReader("tags[]=+B+&tags[]=b&name=+form+"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	is.Eq([]string{"b"}, req.PostForm["tags[]"])
+	is.Eq([]string{"b"}, req.Form["tags[]"])
+	is.Eq("Form", req.PostFormValue("name"))
+	is.Eq([]string{"Form", "Query"}, req.Form["name"])
+	is.Eq("Query", req.URL.Query().Get("name"))
+}
+
+func TestMiddleware_error(t *testing.T) {
+	is := assert.New(t)
+
+	s := MustCompile(map[string]string{
+		"age":  "int",
+		"size": "int",
+	}, CollectErrors)
+	h := Middleware(s)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?age=abc&size=10", nil))
+	is.Eq(http.StatusBadRequest, w.Code)
+	is.Eq("application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var payload map[string]any
+	is.NoErr(json.Unmarshal(w.Body.Bytes(), &payload))
+	is.Eq(float64(400), payload["code"])
+	is.Contains(payload["errors"], "age")
+
+	// bad JSON body
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age"`))
+	r.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	is.Eq(http.StatusBadRequest, w.Code)
+
+	// custom status and payload
+	h = Middleware(s, func(opt *MiddlewareOptions) {
+		opt.ErrorStatus = http.StatusUnprocessableEntity
+		opt.ErrorPayload = func(status int, err error) any {
+			return map[string]any{"ok": false, "status": status}
+		}
+	})(http.NotFoundHandler())
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?age=abc", nil))
+	is.Eq(http.StatusUnprocessableEntity, w.Code)
+	is.Eq(`{"ok":false,"status":422}`, strings.TrimSpace(w.Body.String()))
+
+	// custom error handler
+	h = Middleware(s, func(opt *MiddlewareOptions) {
+		opt.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, "bad input", http.StatusTeapot)
+		}
+	})(http.NotFoundHandler())
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?age=abc", nil))
+	is.Eq(http.StatusTeapot, w.Code)
+
+	// context only
+	var req *http.Request
+	h = Middleware(MustCompile(map[string]string{"name": "trim"}), func(opt *MiddlewareOptions) {
+		opt.ContextOnly = true
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?name=+tom+", nil))
+	is.Eq(" tom ", req.URL.Query().Get("name"))
+	is.Eq("tom", FromContext(req.Context()).String("name"))
+
+	// the JSON body can be read again
+	var body []byte
+	h = Middleware(MustCompile(map[string]string{"name": "trim"}), func(opt *MiddlewareOptions) {
+		opt.ContextOnly = true
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": " tom "}`))
+	r.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	is.Eq(`{"name": " tom "}`, string(body))
+	is.Eq("tom", FromContext(req.Context()).String("name"))
+	is.Nil(FromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()))
+}
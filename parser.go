@@ -0,0 +1,348 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseError error on parse the rule string
+type ParseError struct {
+	// Rule the rule string
+	Rule string
+	// Column position of the error, start from 1
+	Column int
+	// Msg error message
+	Msg string
+}
+
+// Error string
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("filter: parse rule %q error at column %d: %s", e.Rule, e.Column, e.Msg)
+}
+
+// ruleParser parse the rule string to filter steps.
+//
+// Syntax:
+//
+//	rule  = step { "|" step }
+//	step  = name [ ":" args ] | cond
+//	cond  = "if:" name [ ":" args ] "(" rule ")"
+//	args  = arg { "," arg }
+//	arg   = quoted | bare
+//
+// Quoted arg use double or single quotes, backslash can be used to escape
+// the special char in quoted and bare arg. whitespace around name and args
+// will be ignored.
+//
+// Examples:
+//
+//	trim|lower
+//	substr: 0, 2
+//	replace:"a,b","c"
+//	trim:\,\|
+//	str2time:"15:04"
+//	if:notEmpty(trim|lower)
+//	if:fieldEq:type,numeric(int)
+type ruleParser struct {
+	rule string
+	src  []rune
+	pos  int
+	// depth of the cond group
+	depth int
+}
+
+// parseRule parse the rule string to filter steps.
+func parseRule(rule string) ([]*filterStep, error) {
+	p := &ruleParser{rule: rule, src: trimRightSpace([]rune(rule))}
+	return p.parse()
+}
+
+// trimRightSpace remove the trailing spaces of the rule, the escaped space is kept.
+// eg: "str2arr:, " -> "str2arr:,", "trim: " -> "trim:", "trim:\ " is not changed.
+func trimRightSpace(src []rune) []rune {
+	n := len(src)
+	for n > 0 && unicode.IsSpace(src[n-1]) {
+		// count the backslashes before the space, odd is escaped
+		bs := 0
+		for i := n - 2; i >= 0 && src[i] == '\\'; i-- {
+			bs++
+		}
+		if bs%2 == 1 {
+			break
+		}
+		n--
+	}
+	return src[:n]
+}
+
+func (p *ruleParser) parse() (steps []*filterStep, err error) {
+	for !p.eof() {
+		p.skipSpace()
+		if p.depth > 0 && p.peek() == ')' {
+			return steps, nil
+		}
+
+		// skip empty step. eg: "trim||lower"
+		if p.eof() || p.peek() == '|' {
+			p.pos++
+			continue
+		}
+
+		step, err := p.parseStep()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+
+	if p.depth > 0 {
+		return nil, p.errorf("missing ')' for the cond group")
+	}
+	return
+}
+
+func (p *ruleParser) parseStep() (*filterStep, error) {
+	start := p.pos
+	for !p.eof() && isNameChar(p.peek()) {
+		p.pos++
+	}
+
+	name := string(p.src[start:p.pos])
+	if name == "" {
+		return nil, p.errorf("expect filter name, but got %q", p.peek())
+	}
+
+	step := &filterStep{name: name}
+	p.skipSpace()
+	if p.endStep() {
+		return step, nil
+	}
+
+	switch p.peek() {
+	case '|':
+		p.pos++
+		return step, nil
+	case ':':
+		p.pos++
+	default:
+		return nil, p.errorf("unexpected character %q after filter name", p.peek())
+	}
+
+	if name == condStepName {
+		return p.parseCond(step)
+	}
+
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+	step.args = args
+	return step, nil
+}
+
+// parse cond step. eg: "if:notEmpty(trim|lower)"
+func (p *ruleParser) parseCond(step *filterStep) (*filterStep, error) {
+	p.skipSpace()
+	start := p.pos
+	for !p.eof() && isNameChar(p.peek()) {
+		p.pos++
+	}
+
+	if step.cond = string(p.src[start:p.pos]); step.cond == "" {
+		return nil, p.errorf("expect condition name, but got %q", p.peek())
+	}
+
+	p.skipSpace()
+	if p.peek() == ':' {
+		p.pos++
+		p.depth++ // the '(' is the end of args
+		args, err := p.parseArgs()
+		p.depth--
+		if err != nil {
+			return nil, err
+		}
+		step.args = args
+	}
+
+	if p.peek() != '(' {
+		return nil, p.errorf("expect '(' for the cond group, but got %q", p.peek())
+	}
+
+	p.pos++
+	p.depth++
+	group, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	p.depth--
+	p.pos++ // skip ')'
+	step.group = group
+
+	p.skipSpace()
+	if !p.endStep() {
+		if p.peek() != '|' {
+			return nil, p.errorf("unexpected character %q after cond group", p.peek())
+		}
+		p.pos++
+	}
+	return step, nil
+}
+
+// check is the end of a step. eof or ')' in the cond group
+func (p *ruleParser) endStep() bool {
+	return p.eof() || p.depth > 0 && p.peek() == ')'
+}
+
+// check is the end of an arg.
+func (p *ruleParser) endArg(ch rune) bool {
+	return ch == ',' || ch == '|' || p.depth > 0 && (ch == ')' || ch == '(')
+}
+
+func (p *ruleParser) parseArgs() (args []string, err error) {
+	// compatible: one char arg, eg: "str2arr:," "str2arr:;"
+	if p.pos+1 == len(p.src) || p.pos+1 < len(p.src) && p.endArg(p.src[p.pos+1]) && p.src[p.pos+1] != ',' {
+		if ch := p.peek(); ch != '"' && ch != '\'' && ch != '\\' {
+			p.pos++
+			if !p.endStep() && p.peek() == '|' {
+				p.pos++
+			}
+			return []string{string(ch)}, nil
+		}
+	}
+
+	// no args. eg: "trim:" "trim:|lower"
+	if p.skipSpace(); p.endStep() || p.endArg(p.peek()) && p.peek() != ',' {
+		if p.peek() == '|' {
+			p.pos++
+		}
+		return nil, nil
+	}
+
+	for {
+		p.skipSpace()
+		var arg string
+		if ch := p.peek(); ch == '"' || ch == '\'' {
+			if arg, err = p.parseQuoted(ch); err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+		} else if arg, err = p.parseBare(); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		if p.endStep() {
+			return args, nil
+		}
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '|':
+			p.pos++
+			return args, nil
+		case '(':
+			if p.depth > 0 {
+				return args, nil
+			}
+			fallthrough
+		default:
+			return nil, p.errorf("unexpected character %q after quoted arg", p.peek())
+		}
+	}
+}
+
+func (p *ruleParser) parseQuoted(quote rune) (string, error) {
+	start := p.pos
+	p.pos++ // skip open quote
+
+	var sb strings.Builder
+	for !p.eof() {
+		ch := p.next()
+		switch ch {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated escape sequence")
+			}
+			sb.WriteRune(unescape(p.next()))
+		default:
+			sb.WriteRune(ch)
+		}
+	}
+
+	p.pos = start
+	return "", p.errorf("unterminated quoted arg")
+}
+
+func (p *ruleParser) parseBare() (string, error) {
+	var arg []rune
+	// length without the trailing whitespace
+	var size int
+	for !p.eof() {
+		ch := p.peek()
+		if p.endArg(ch) {
+			break
+		}
+
+		p.pos++
+		if ch == '\\' {
+			if p.eof() {
+				return "", p.errorf("unterminated escape sequence")
+			}
+			arg = append(arg, unescape(p.next()))
+			size = len(arg)
+			continue
+		}
+
+		arg = append(arg, ch)
+		if !unicode.IsSpace(ch) {
+			size = len(arg)
+		}
+	}
+	return string(arg[:size]), nil
+}
+
+func (p *ruleParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *ruleParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *ruleParser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *ruleParser) next() rune {
+	ch := p.src[p.pos]
+	p.pos++
+	return ch
+}
+
+func (p *ruleParser) errorf(format string, args ...any) *ParseError {
+	return &ParseError{Rule: p.rule, Column: p.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isNameChar(ch rune) bool {
+	return ch == '_' || ch == '-' || ch == '.' || unicode.IsLetter(ch) || unicode.IsDigit(ch)
+}
+
+func unescape(ch rune) rune {
+	switch ch {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	}
+	return ch
+}
@@ -0,0 +1,118 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParseRule(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		rule  string
+		names []string
+		args  [][]string
+	}{
+		{"trim|lower", []string{"trim", "lower"}, [][]string{nil, nil}},
+		{" trim | lower ", []string{"trim", "lower"}, [][]string{nil, nil}},
+		{"|trim||lower|", []string{"trim", "lower"}, [][]string{nil, nil}},
+		{"trim:|lower", []string{"trim", "lower"}, [][]string{nil, nil}},
+		{"substr:0,2", []string{"substr"}, [][]string{{"0", "2"}}},
+		{"substr: 0 , 2 |upper", []string{"substr", "upper"}, [][]string{{"0", "2"}, nil}},
+		{"str2arr:,", []string{"str2arr"}, [][]string{{","}}},
+		{"str2arr:;|unique", []string{"str2arr", "unique"}, [][]string{{";"}, nil}},
+		{"strToSlice:|", []string{"strToSlice"}, [][]string{{"|"}}},
+		{`replace:"a,b","c"`, []string{"replace"}, [][]string{{"a,b", "c"}}},
+		{`replace:'a|b' , "" | trim`, []string{"replace", "trim"}, [][]string{{"a|b", ""}, nil}},
+		{`trim:\,\|`, []string{"trim"}, [][]string{{",|"}}},
+		{`trim:" "`, []string{"trim"}, [][]string{{" "}}},
+		{`trim:a\ `, []string{"trim"}, [][]string{{"a "}}},
+		// the trailing spaces are ignored
+		{"str2arr:, ", []string{"str2arr"}, [][]string{{","}}},
+		{"trim: ", []string{"trim"}, [][]string{nil}},
+		{"trim:a\\\\ ", []string{"trim"}, [][]string{{`a\`}}},
+		{`wrap:"say \"hi\"\n"`, []string{"wrap"}, [][]string{{"say \"hi\"\n"}}},
+		{"str2time:15:04", []string{"str2time"}, [][]string{{"15:04"}}},
+		{`str2time:"2006-01-02 15:04"`, []string{"str2time"}, [][]string{{"2006-01-02 15:04"}}},
+	}
+
+	for _, tt := range tests {
+		steps, err := parseRule(tt.rule)
+		is.NoErr(err, tt.rule)
+		is.Len(steps, len(tt.names), tt.rule)
+		for i, step := range steps {
+			is.Eq(tt.names[i], step.name, tt.rule)
+			is.Eq(tt.args[i], step.args, tt.rule)
+		}
+	}
+
+	steps, err := parseRule(" | ")
+	is.NoErr(err)
+	is.Empty(steps)
+}
+
+func TestParseRule_cond(t *testing.T) {
+	is := assert.New(t)
+
+	steps, err := parseRule("trim|if:notEmpty(lower|str2arr:;)|unique")
+	is.NoErr(err)
+	is.Len(steps, 3)
+	is.Eq("if", steps[1].name)
+	is.Eq("notEmpty", steps[1].cond)
+	is.Nil(steps[1].args)
+	is.Len(steps[1].group, 2)
+	is.Eq("lower", steps[1].group[0].name)
+	is.Eq([]string{";"}, steps[1].group[1].args)
+	is.Eq("unique", steps[2].name)
+
+	steps, err = parseRule(`if: fieldEq:type, "numeric" ( trim | int ) `)
+	is.NoErr(err)
+	is.Len(steps, 1)
+	is.Eq("fieldEq", steps[0].cond)
+	is.Eq([]string{"type", "numeric"}, steps[0].args)
+	is.Len(steps[0].group, 2)
+
+	// nested
+	steps, err = parseRule("if:isString(trim|if:eq:a(upper))|lower")
+	is.NoErr(err)
+	is.Len(steps, 2)
+	is.Eq("eq", steps[0].group[1].cond)
+	is.Eq([]string{"a"}, steps[0].group[1].args)
+	is.Eq("upper", steps[0].group[1].group[0].name)
+
+	tests := map[string]string{
+		"if:(trim)":          `filter: parse rule "if:(trim)" error at column 4: expect condition name, but got '('`,
+		"if:notEmpty|trim":   `filter: parse rule "if:notEmpty|trim" error at column 12: expect '(' for the cond group, but got '|'`,
+		"if:notEmpty(trim":   `filter: parse rule "if:notEmpty(trim" error at column 17: missing ')' for the cond group`,
+		"if:notEmpty(trim)x": `filter: parse rule "if:notEmpty(trim)x" error at column 18: unexpected character 'x' after cond group`,
+	}
+	for rule, want := range tests {
+		_, err = parseRule(rule)
+		is.ErrMsg(err, want)
+	}
+}
+
+func TestParseRule_error(t *testing.T) {
+	is := assert.New(t)
+
+	tests := map[string]string{
+		`trim:"abc`:    `filter: parse rule "trim:\"abc" error at column 6: unterminated quoted arg`,
+		`trim:"a"b`:    `filter: parse rule "trim:\"a\"b" error at column 9: unexpected character 'b' after quoted arg`,
+		`trim:abc\`:    `filter: parse rule "trim:abc\\" error at column 10: unterminated escape sequence`,
+		`trim|:abc`:    `filter: parse rule "trim|:abc" error at column 6: expect filter name, but got ':'`,
+		`tr im`:        `filter: parse rule "tr im" error at column 4: unexpected character 'i' after filter name`,
+		`trim|"a"`:     `filter: parse rule "trim|\"a\"" error at column 6: expect filter name, but got '"'`,
+		`trim:'a'|tr$`: `filter: parse rule "trim:'a'|tr$" error at column 12: unexpected character '$' after filter name`,
+	}
+
+	for rule, want := range tests {
+		_, err := parseRule(rule)
+		is.ErrMsg(err, want)
+
+		var pe *ParseError
+		is.True(errors.As(err, &pe))
+		is.Eq(rule, pe.Rule)
+	}
+}
@@ -0,0 +1,218 @@
+package filter
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// expandPath expand the wildcard path to real paths by the data.
+//
+// Wildcard:
+//
+//   - "*" match any key or index. eg: "items.*.name" -> "items.0.name", "items.1.name"
+//   - "**" match zero or more levels. eg: "**.name" -> "name", "user.name", "items.0.name"
+func expandPath(data map[string]any, path string) []string {
+	var paths []string
+	exist := make(map[string]bool)
+	expandKeys(data, strings.Split(path, "."), "", func(p string) {
+		if !exist[p] {
+			exist[p] = true
+			paths = append(paths, p)
+		}
+	})
+	return paths
+}
+
+func expandKeys(data any, keys []string, prefix string, collect func(p string)) {
+	if len(keys) == 0 {
+		if prefix != "" {
+			collect(prefix)
+		}
+		return
+	}
+
+	switch key := keys[0]; key {
+	case "**":
+		// match zero level
+		expandKeys(data, keys[1:], prefix, collect)
+		// match one or more levels
+		for _, k := range childKeys(data) {
+			child, _ := getChild(data, k)
+			expandKeys(child, keys, joinPath(prefix, k), collect)
+		}
+	case "*":
+		for _, k := range childKeys(data) {
+			child, _ := getChild(data, k)
+			expandKeys(child, keys[1:], joinPath(prefix, k), collect)
+		}
+	default:
+		if child, ok := getChild(data, key); ok {
+			expandKeys(child, keys[1:], joinPath(prefix, key), collect)
+		}
+	}
+}
+
+// childKeys get sorted keys of the map, or indexes of the slice.
+func childKeys(data any) (keys []string) {
+	switch typData := data.(type) {
+	case map[string]any:
+		for k := range typData {
+			keys = append(keys, k)
+		}
+	case map[string]string:
+		for k := range typData {
+			keys = append(keys, k)
+		}
+	case map[any]any:
+		for k := range typData {
+			if sk, ok := k.(string); ok {
+				keys = append(keys, sk)
+			}
+		}
+	default:
+		if isSlice(data) {
+			ln := reflect.ValueOf(data).Len()
+			for i := 0; i < ln; i++ {
+				keys = append(keys, strconv.Itoa(i))
+			}
+		}
+		return
+	}
+
+	sort.Strings(keys)
+	return
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// setByPath set value to the dst map by key path. eg "top" "top.sub" "list.0.name"
+//
+// The sub container type will mirror the raw data: use []any for
+// the raw value is a slice, otherwise use map[string]any.
+func setByPath(dst, raw map[string]any, path string, val any) {
+	// the raw data has the flat key. eg: "top.sub"
+	if _, ok := raw[path]; ok || strings.IndexByte(path, '.') < 1 {
+		dst[path] = val
+		return
+	}
+
+	setByKeys(dst, strings.Split(path, "."), val, raw)
+}
+
+// setByKeys set value to the container by path keys, returns the container.
+func setByKeys(dst any, keys []string, val any, raw any) any {
+	key := keys[0]
+	if len(keys) == 1 {
+		return setChild(dst, key, val)
+	}
+
+	rawChild, _ := getChild(raw, key)
+	child, _ := getChild(dst, key)
+	if !isContainer(child) {
+		if isSlice(rawChild) && isIndex(keys[1]) {
+			child = make([]any, 0)
+		} else {
+			child = make(map[string]any)
+		}
+	}
+
+	return setChild(dst, key, setByKeys(child, keys[1:], val, rawChild))
+}
+
+// getChild get child value from map or slice
+func getChild(data any, key string) (any, bool) {
+	switch typData := data.(type) {
+	case map[string]any:
+		val, ok := typData[key]
+		return val, ok
+	case map[string]string:
+		val, ok := typData[key]
+		return val, ok
+	case map[any]any:
+		val, ok := typData[key]
+		return val, ok
+	default:
+		if !isSlice(data) {
+			return nil, false
+		}
+
+		rv := reflect.ValueOf(data)
+		if idx, err := strconv.Atoi(key); err == nil && idx >= 0 && idx < rv.Len() {
+			return rv.Index(idx).Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// setChild set child value to the map or slice, returns the container.
+func setChild(data any, key string, val any) any {
+	if sl, ok := data.([]any); ok {
+		idx, err := strconv.Atoi(key)
+		if err == nil && idx >= 0 {
+			for len(sl) <= idx {
+				sl = append(sl, nil)
+			}
+			sl[idx] = val
+			return sl
+		}
+		// not an index key, convert to map
+		data = sliceToMap(sl)
+	}
+
+	if mp, ok := data.(map[string]any); ok {
+		mp[key] = val
+		return mp
+	}
+	return map[string]any{key: val}
+}
+
+// deepCopy copy the map[string]any and []any value recursively.
+func deepCopy(val any) any {
+	switch tv := val.(type) {
+	case map[string]any:
+		mp := make(map[string]any, len(tv))
+		for k, v := range tv {
+			mp[k] = deepCopy(v)
+		}
+		return mp
+	case []any:
+		sl := make([]any, len(tv))
+		for i, v := range tv {
+			sl[i] = deepCopy(v)
+		}
+		return sl
+	}
+	return val
+}
+
+func sliceToMap(sl []any) map[string]any {
+	mp := make(map[string]any, len(sl))
+	for i, v := range sl {
+		mp[strconv.Itoa(i)] = v
+	}
+	return mp
+}
+
+func isContainer(val any) bool {
+	switch val.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+func isSlice(val any) bool {
+	return val != nil && reflect.TypeOf(val).Kind() == reflect.Slice
+}
+
+func isIndex(key string) bool {
+	_, err := strconv.Atoi(key)
+	return err == nil
+}
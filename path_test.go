@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestSetByPath(t *testing.T) {
+	is := assert.New(t)
+
+	raw := map[string]any{
+		"top.sub": "flat",
+		"user":    map[string]any{"name": "inhere"},
+		"items":   []any{map[string]any{"sku": "a"}, map[string]any{"sku": "b"}},
+		"list":    []map[string]any{{"id": 1}},
+	}
+	dst := make(map[string]any)
+
+	setByPath(dst, raw, "name", "v0")
+	setByPath(dst, raw, "top.sub", "v1")
+	setByPath(dst, raw, "user.name", "v2")
+	setByPath(dst, raw, "user.info.age", 23)
+	setByPath(dst, raw, "items.1.sku", "B")
+	setByPath(dst, raw, "items.0.sku", "A")
+	setByPath(dst, raw, "list.0.id", 2)
+	setByPath(dst, raw, "list.key", 3)
+
+	is.Eq(map[string]any{
+		"name":    "v0",
+		"top.sub": "v1",
+		"user": map[string]any{
+			"name": "v2",
+			"info": map[string]any{"age": 23},
+		},
+		"items": []any{
+			map[string]any{"sku": "A"},
+			map[string]any{"sku": "B"},
+		},
+		"list": map[string]any{
+			"0":   map[string]any{"id": 2},
+			"key": 3,
+		},
+	}, dst)
+}
+
+func TestExpandPath(t *testing.T) {
+	is := assert.New(t)
+
+	data := map[string]any{
+		"name": "n0",
+		"user": map[string]any{"name": "n1", "tags": []string{"a", "b"}},
+		"items": []any{
+			map[string]any{"name": "n2", "sku": "s0"},
+			map[string]any{"name": "n3"},
+		},
+		"list": []map[string]any{{"name": "n4"}},
+		"smap": map[string]string{"k0": "v0"},
+	}
+
+	is.Eq([]string{"items.0.name", "items.1.name"}, expandPath(data, "items.*.name"))
+	is.Eq([]string{"items.0.sku"}, expandPath(data, "items.*.sku"))
+	is.Eq([]string{"list.0.name"}, expandPath(data, "list.*.name"))
+	is.Eq([]string{"user.tags.0", "user.tags.1"}, expandPath(data, "user.tags.*"))
+	is.Eq([]string{"smap.k0"}, expandPath(data, "smap.*"))
+	is.Eq([]string{"name", "items.0.name", "items.1.name", "list.0.name", "user.name"}, expandPath(data, "**.name"))
+	is.Empty(expandPath(data, "not-exist.*"))
+	is.Empty(expandPath(data, "name.*"))
+}
@@ -0,0 +1,92 @@
+package filter
+
+import "time"
+
+// Func is a typed filter func, convert the value from type A to B.
+//
+// It can be composed by Pipe() and Chain() with compile-time type checking.
+type Func[A, B any] func(A) (B, error)
+
+// F wrap a func without error to the Func. eg: F(strings.TrimSpace)
+func F[A, B any](fn func(A) B) Func[A, B] {
+	return func(val A) (B, error) {
+		return fn(val), nil
+	}
+}
+
+// Pipe compose the same type filter funcs to one, they will be applied in order.
+// will stop on the first error.
+//
+// Usage:
+//
+//	norm := filter.Pipe(filter.TrimF, filter.LowerF)
+//	s, err := norm(" Inhere ") // "inhere"
+func Pipe[T any](fns ...Func[T, T]) Func[T, T] {
+	return func(val T) (T, error) {
+		var err error
+		for _, fn := range fns {
+			if val, err = fn(val); err != nil {
+				return val, err
+			}
+		}
+		return val, nil
+	}
+}
+
+// Chain compose two filter funcs, the output of first is the input of second.
+//
+// The type params are (In, Out, Mid), so the Mid type can be inferred.
+//
+// Usage:
+//
+//	toAge := filter.Chain[string, int](filter.TrimF, filter.ToIntF)
+//	age, err := toAge(" 23 ") // 23
+//
+//	// chain more funcs
+//	toIDs := filter.Chain[string, []int](filter.Pipe(filter.TrimF, filter.LowerF), filter.StrToIntsF)
+func Chain[A, C, B any](first Func[A, B], second Func[B, C]) Func[A, C] {
+	return func(val A) (C, error) {
+		mid, err := first(val)
+		if err != nil {
+			var zero C
+			return zero, err
+		}
+		return second(mid)
+	}
+}
+
+// typed string filters, built on the same filter funcs.
+var (
+	TrimF       = F(func(s string) string { return Trim(s) })
+	LowerF      = F(Lowercase)
+	UpperF      = F(Uppercase)
+	LowerFirstF = F(LowerFirst)
+	UpperFirstF = F(UpperFirst)
+	UpperWordF  = F(UpperWord)
+	SnakeF      = F(func(s string) string { return SnakeCase(s) })
+	CamelF      = F(func(s string) string { return CamelCase(s) })
+	EmailF      = F(Email)
+	URLEncodeF  = F(URLEncode)
+	URLDecodeF  = F(URLDecode)
+	EscapeJSF   = F(EscapeJS)
+	EscapeHTMLF = F(EscapeHTML)
+)
+
+// typed converters, built on the same converter funcs.
+var (
+	ToIntF     Func[string, int]       = func(s string) (int, error) { return ToInt(Trim(s)) }
+	ToInt64F   Func[string, int64]     = func(s string) (int64, error) { return ToInt64(Trim(s)) }
+	ToUintF    Func[string, uint]      = func(s string) (uint, error) { return ToUint(Trim(s)) }
+	ToFloatF   Func[string, float64]   = ToFloat
+	ToBoolF    Func[string, bool]      = func(s string) (bool, error) { return ToBool(Trim(s)) }
+	StrToIntsF Func[string, []int]     = func(s string) ([]int, error) { return StrToInts(s) }
+	StrToTimeF Func[string, time.Time] = func(s string) (time.Time, error) { return StrToTime(s) }
+
+	StringsToIntsF Func[[]string, []int] = StringsToInts
+)
+
+// typed slice filters
+var (
+	StrToSliceF  = F(func(s string) []string { return StrToSlice(s) })
+	TrimStringsF = F(func(ss []string) []string { return TrimStrings(ss) })
+)
@@ -0,0 +1,77 @@
+package filter_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/filter"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestPipe(t *testing.T) {
+	is := assert.New(t)
+
+	norm := filter.Pipe(filter.TrimF, filter.LowerF, filter.UpperFirstF)
+	s, err := norm(" INHERE ")
+	is.NoErr(err)
+	is.Eq("Inhere", s)
+
+	// custom func
+	norm = filter.Pipe[string](filter.TrimF, filter.F(strings.ToUpper), func(s string) (string, error) {
+		if s == "" {
+			return s, errors.New("empty value")
+		}
+		return s + "!", nil
+	})
+	s, err = norm(" hi ")
+	is.NoErr(err)
+	is.Eq("HI!", s)
+	_, err = norm("  ")
+	is.ErrMsg(err, "empty value")
+
+	// no funcs
+	s, err = filter.Pipe[string]()("abc")
+	is.NoErr(err)
+	is.Eq("abc", s)
+}
+
+func TestChain(t *testing.T) {
+	is := assert.New(t)
+
+	toAge := filter.Chain[string, int](filter.TrimF, filter.ToIntF)
+	age, err := toAge(" 23 ")
+	is.NoErr(err)
+	is.Eq(23, age)
+	_, err = toAge("abc")
+	is.Err(err)
+
+	toIDs := filter.Chain[string, []int](
+		filter.Chain[string, []string](filter.TrimF, filter.StrToSliceF),
+		filter.StringsToIntsF,
+	)
+	ids, err := toIDs(" 1, 2,3 ")
+	is.NoErr(err)
+	is.Eq([]int{1, 2, 3}, ids)
+
+	toTime := filter.Chain[string, time.Time](filter.TrimF, filter.StrToTimeF)
+	tm, err := toTime(" 2018-10-16 12:34 ")
+	is.NoErr(err)
+	is.Eq(2018, tm.Year())
+
+	toFloat := filter.Chain[string, float64](filter.Pipe(filter.TrimF, filter.URLDecodeF), filter.ToFloatF)
+	fv, err := toFloat(" 12.5 ")
+	is.NoErr(err)
+	is.Eq(12.5, fv)
+
+	ok, err := filter.ToBoolF(" yes ")
+	is.NoErr(err)
+	is.True(ok)
+
+	// use as a typed filter
+	filter.AddFilter("toAge", toAge)
+	val, err := filter.Apply("toAge", " 34 ", nil)
+	is.NoErr(err)
+	is.Eq(34, val)
+}
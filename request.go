@@ -0,0 +1,267 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MaxBodySize the max size of the request body on read JSON and form data. default is 10MB
+var MaxBodySize int64 = 10 << 20
+
+// FromValues create a Filtration from the url.Values. eg: r.URL.Query(), r.PostForm
+//
+// The single value will be string, the repeated values will be []string.
+// The key with bracket notation will be expanded into nested maps:
+//
+//	user[name]=tom      -> {"user": {"name": "tom"}}
+//	tags[]=a            -> {"tags": []string{"a"}}
+//	user[tags][]=a&...  -> {"user": {"tags": []string{"a", ...}}}
+func FromValues(values url.Values) *Filtration {
+	return New(valuesToMap(values))
+}
+
+// FromJSON create a Filtration from the JSON object, will read at most MaxBodySize bytes.
+func FromJSON(r io.Reader) (*Filtration, error) {
+	data, err := readJSON(r)
+	if err != nil {
+		return nil, err
+	}
+	return New(data), nil
+}
+
+// FromMultipartForm create a Filtration from the multipart form of the request.
+// the form values will be parsed like FromValues(), the files are *multipart.FileHeader
+// or []*multipart.FileHeader for repeated keys.
+func FromMultipartForm(r *http.Request) (*Filtration, error) {
+	data, err := readMultipartForm(r)
+	if err != nil {
+		return nil, err
+	}
+	return New(data), nil
+}
+
+// FromRequest create a Filtration from the request. will read the query
+// params and the body by the Content-Type, the body values will override
+// the query params that have same key. support body types:
+//
+//	application/json
+//	application/x-www-form-urlencoded
+//	multipart/form-data
+//
+// NOTE: the JSON body will be reset after read, it can be read again. the
+// form body will be consumed, the values can be got from r.PostForm
+func FromRequest(r *http.Request) (*Filtration, error) {
+	data, err := requestData(r)
+	if err != nil {
+		return nil, err
+	}
+	return New(data), nil
+}
+
+// requestData read the query params and body data of the request.
+func requestData(r *http.Request) (map[string]any, error) {
+	data, body, err := readRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, val := range body {
+		data[key] = val
+	}
+	return data, nil
+}
+
+// readRequest read the query params and body data of the request.
+func readRequest(r *http.Request) (query, body map[string]any, err error) {
+	query = valuesToMap(r.URL.Query())
+	if r.Body == nil || r.Body == http.NoBody {
+		return query, nil, nil
+	}
+
+	switch bodyType(r) {
+	case bodyJSON:
+		var bs []byte
+		if bs, err = readBody(r.Body); err == nil {
+			// reset the body for the next handler
+			r.Body = io.NopCloser(bytes.NewReader(bs))
+			body, err = decodeJSON(bs)
+		}
+	case bodyMultipart:
+		body, err = readMultipartForm(r)
+	case bodyForm:
+		r.Body = http.MaxBytesReader(nil, r.Body, MaxBodySize)
+		if err = r.ParseForm(); err == nil {
+			body = valuesToMap(r.PostForm)
+		}
+	}
+
+	if err != nil {
+		return nil, nil, bodyError(err)
+	}
+	return query, body, nil
+}
+
+// request body types
+const (
+	bodyNone uint8 = iota
+	bodyJSON
+	bodyForm
+	bodyMultipart
+)
+
+// get the body type of the request by the Content-Type
+func bodyType(r *http.Request) uint8 {
+	ctype, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case ctype == "application/json" || strings.HasSuffix(ctype, "+json"):
+		return bodyJSON
+	case ctype == "multipart/form-data":
+		return bodyMultipart
+	case ctype == "application/x-www-form-urlencoded":
+		return bodyForm
+	}
+	return bodyNone
+}
+
+func readJSON(r io.Reader) (map[string]any, error) {
+	bs, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(bs)
+}
+
+// readBody read at most MaxBodySize bytes, returns ErrBodyTooLarge on over the limit.
+func readBody(r io.Reader) ([]byte, error) {
+	bs, err := io.ReadAll(io.LimitReader(r, MaxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(bs)) > MaxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+	return bs, nil
+}
+
+// decodeJSON decode the JSON object, the numbers will be int, uint64 or float64.
+// see jsonNumber()
+func decodeJSON(bs []byte) (map[string]any, error) {
+	// the empty body is an empty object
+	data := make(map[string]any)
+	if len(bytes.TrimSpace(bs)) == 0 {
+		return data, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("filter: decode JSON body error: %w", err)
+	}
+	if data == nil {
+		data = make(map[string]any)
+	}
+	return jsonNumber(data).(map[string]any), nil
+}
+
+func readMultipartForm(r *http.Request) (map[string]any, error) {
+	r.Body = http.MaxBytesReader(nil, r.Body, MaxBodySize)
+	if err := r.ParseMultipartForm(MaxBodySize); err != nil {
+		return nil, bodyError(err)
+	}
+
+	data := valuesToMap(r.MultipartForm.Value)
+	for key, files := range r.MultipartForm.File {
+		var val any = files
+		if len(files) == 1 && !strings.HasSuffix(key, "[]") {
+			val = files[0]
+		}
+		setByBracketKey(data, key, val)
+	}
+	return data, nil
+}
+
+// convert the body read error, returns ErrBodyTooLarge on the body size is over limit.
+func bodyError(err error) error {
+	var mbErr *http.MaxBytesError
+	if errors.As(err, &mbErr) || errors.Is(err, multipart.ErrMessageTooLarge) {
+		return ErrBodyTooLarge
+	}
+	return err
+}
+
+// valuesToMap convert the url.Values to map, will expand the bracket notation keys.
+func valuesToMap(values url.Values) map[string]any {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	// sort keys for the conflict keys. eg: "user" and "user[name]"
+	sort.Strings(keys)
+
+	data := make(map[string]any, len(values))
+	for _, key := range keys {
+		vs := values[key]
+		var val any = vs
+		if len(vs) == 1 && !strings.HasSuffix(key, "[]") {
+			val = vs[0]
+		}
+		setByBracketKey(data, key, val)
+	}
+	return data
+}
+
+// setByBracketKey set value to the data by the bracket notation key. eg: "user[name]"
+func setByBracketKey(data map[string]any, key string, val any) {
+	keys := parseBracketKey(key)
+	last := len(keys) - 1
+
+	mp := data
+	for _, k := range keys[:last] {
+		sub, ok := mp[k].(map[string]any)
+		if !ok {
+			sub = make(map[string]any)
+			mp[k] = sub
+		}
+		mp = sub
+	}
+	mp[keys[last]] = val
+}
+
+// parseBracketKey parse the bracket notation key to the key path.
+//
+//	"user[name]" -> ["user", "name"]
+//	"tags[]"     -> ["tags"]
+//
+// will return the raw key on it is invalid. eg: "user[name"
+func parseBracketKey(key string) []string {
+	pos := strings.IndexByte(key, '[')
+	if pos <= 0 || !strings.HasSuffix(key, "]") {
+		return []string{key}
+	}
+
+	keys := []string{key[:pos]}
+	rest := strings.TrimSuffix(key[pos:], "[]")
+	for rest != "" {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 0 {
+			return []string{key}
+		}
+
+		sub := rest[1:end]
+		if sub == "" || strings.ContainsAny(sub, "[") {
+			return []string{key}
+		}
+		keys = append(keys, sub)
+		rest = rest[end+1:]
+	}
+	return keys
+}
@@ -0,0 +1,187 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestFromValues(t *testing.T) {
+	is := assert.New(t)
+
+	values, err := url.ParseQuery("name=+inhere+&tags[]=a&tags[]=b&ids=1&ids=2&user[name]=tom&user[tags][]=x&user[addr][city]=sz&bad[key=v")
+	is.NoErr(err)
+
+	f := FromValues(values)
+	is.Eq(map[string]any{
+		"name": " inhere ",
+		"tags": []string{"a", "b"},
+		"ids":  []string{"1", "2"},
+		"user": map[string]any{
+			"name": "tom",
+			"tags": []string{"x"},
+			"addr": map[string]any{"city": "sz"},
+		},
+		"bad[key": "v",
+	}, f.RawData())
+
+	f.AddRule("name", "trim").AddFilters("upper")
+	f.AddRule("user.name", "upper")
+	f.AddRule("ids", "stringsToInts")
+	is.NoErr(f.Filtering())
+	is.Eq("INHERE", f.String("name"))
+	is.Eq("TOM", f.String("user.name"))
+	is.Eq([]int{1, 2}, f.Ints("ids"))
+
+	is.Eq([]string{"a", "b", "c"}, parseBracketKey("a[b][c]"))
+	is.Eq([]string{"a"}, parseBracketKey("a[]"))
+	is.Eq([]string{"a[b]c"}, parseBracketKey("a[b]c"))
+	is.Eq([]string{"[a]"}, parseBracketKey("[a]"))
+	is.Eq([]string{"a[][b]"}, parseBracketKey("a[][b]"))
+}
+
+func TestFromJSON(t *testing.T) {
+	is := assert.New(t)
+
+	f, err := FromJSON(strings.NewReader(`{"name": " inhere ", "age": "23", "user": {"city": " sz "}}`))
+	is.NoErr(err)
+	f.AddRules(map[string]string{"name": "trim", "age": "int", "user.city": "trim"})
+	is.NoErr(f.Filtering())
+	is.Eq("inhere", f.String("name"))
+	is.Eq(23, f.Int("age"))
+	is.Eq("sz", f.String("user.city"))
+
+	_, err = FromJSON(strings.NewReader(`[1, 2]`))
+	is.ErrSubMsg(err, "filter: decode JSON body error")
+
+	f, err = FromJSON(strings.NewReader(`null`))
+	is.NoErr(err)
+	is.Empty(f.RawData())
+
+	// empty body
+	f, err = FromJSON(strings.NewReader(" \n"))
+	is.NoErr(err)
+	is.Empty(f.RawData())
+
+	// numbers are kept exactly
+	f, err = FromJSON(strings.NewReader(`{"n": 23, "price": 1.5, "id": 12345678901234567890,
+"big": 123456789012345678901234, "list": [1, -2], "user": {"age": 3}}`))
+	is.NoErr(err)
+	is.Eq(map[string]any{
+		"n":     23,
+		"price": 1.5,
+		"id":    uint64(12345678901234567890),
+		"big":   json.Number("123456789012345678901234"),
+		"list":  []any{1, -2},
+		"user":  map[string]any{"age": 3},
+	}, f.RawData())
+	bs, err := json.Marshal(f.RawData())
+	is.NoErr(err)
+	is.StrContains(string(bs), `"big":123456789012345678901234`)
+	is.StrContains(string(bs), `"id":12345678901234567890`)
+
+	// size limit
+	old := MaxBodySize
+	MaxBodySize = 10
+	defer func() { MaxBodySize = old }()
+
+	_, err = FromJSON(strings.NewReader(`{"name": "inhere"}`))
+	is.True(errors.Is(err, ErrBodyTooLarge))
+}
+
+func TestFromRequest(t *testing.T) {
+	is := assert.New(t)
+
+	// query and JSON body
+	r := httptest.NewRequest(http.MethodPost, "/?page=2&name=query", strings.NewReader(`{"name": " inhere "}`))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	f, err := FromRequest(r)
+	is.NoErr(err)
+	is.Eq(map[string]any{"page": "2", "name": " inhere "}, f.RawData())
+
+	// urlencoded form
+	r = httptest.NewRequest(http.MethodPost, "/?page=2", strings.NewReader("name=tom&tags[]=go"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	f, err = FromRequest(r)
+	is.NoErr(err)
+	is.Eq(map[string]any{"page": "2", "name": "tom", "tags": []string{"go"}}, f.RawData())
+
+	// empty JSON body
+	r = httptest.NewRequest(http.MethodPost, "/?page=2", strings.NewReader(""))
+	r.Header.Set("Content-Type", "application/json")
+	f, err = FromRequest(r)
+	is.NoErr(err)
+	is.Eq(map[string]any{"page": "2"}, f.RawData())
+
+	// no body
+	r = httptest.NewRequest(http.MethodGet, "/?page=2", nil)
+	f, err = FromRequest(r)
+	is.NoErr(err)
+	is.Eq(map[string]any{"page": "2"}, f.RawData())
+
+	// bad JSON
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name"`))
+	r.Header.Set("Content-Type", "application/json")
+	_, err = FromRequest(r)
+	is.Err(err)
+
+	// size limit
+	old := MaxBodySize
+	MaxBodySize = 10
+	defer func() { MaxBodySize = old }()
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=inhere&age=23"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err = FromRequest(r)
+	is.True(errors.Is(err, ErrBodyTooLarge))
+}
+
+func TestFromMultipartForm(t *testing.T) {
+	is := assert.New(t)
+
+	newRequest := func() *http.Request {
+		buf := new(bytes.Buffer)
+		w := multipart.NewWriter(buf)
+		is.NoErr(w.WriteField("name", " inhere "))
+		is.NoErr(w.WriteField("user[age]", "23"))
+		fw, err := w.CreateFormFile("avatar", "a.png")
+		is.NoErr(err)
+		_, err = fw.Write([]byte("png"))
+		is.NoErr(err)
+		is.NoErr(w.Close())
+
+		r := httptest.NewRequest(http.MethodPost, "/", buf)
+		r.Header.Set("Content-Type", w.FormDataContentType())
+		return r
+	}
+
+	f, err := FromMultipartForm(newRequest())
+	is.NoErr(err)
+	is.Eq(" inhere ", f.RawData()["name"])
+	is.Eq(map[string]any{"age": "23"}, f.RawData()["user"])
+	fh, ok := f.RawData()["avatar"].(*multipart.FileHeader)
+	is.True(ok)
+	is.Eq("a.png", fh.Filename)
+
+	f, err = FromRequest(newRequest())
+	is.NoErr(err)
+	f.AddRule("user.age", "int")
+	is.NoErr(f.Filtering())
+	is.Eq(23, f.Int("user.age"))
+
+	// size limit
+	old := MaxBodySize
+	MaxBodySize = 10
+	defer func() { MaxBodySize = old }()
+
+	_, err = FromMultipartForm(newRequest())
+	is.True(errors.Is(err, ErrBodyTooLarge))
+}
@@ -0,0 +1,512 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*************************************************************
+ * filtering rule
+ *************************************************************/
+
+// DefaultMode for use the default value of the rule
+type DefaultMode uint8
+
+// Default value modes
+const (
+	// DefaultOnMissing use default value on the field is missing. it is the default mode.
+	DefaultOnMissing DefaultMode = iota
+	// DefaultOnNil use default value on the field is missing or value is nil.
+	DefaultOnNil
+	// DefaultOnEmpty use default value on the field is missing or value is empty.
+	// eg: nil, empty string, empty slice and map
+	DefaultOnEmpty
+	// DefaultAfterFilter use default value on the field is missing or
+	// the filtered result is empty. eg: the value is " " and filter is "trim"
+	DefaultAfterFilter
+)
+
+// funcStepName the filter name of the custom func step, see Rule.AddFunc()
+const funcStepName = "func"
+
+// requiredName the filter name of the error on the required field is empty, see Rule.Required()
+const requiredName = "required"
+
+// contextName the filter name of the error on the ctx is done before apply the rule
+const contextName = "context"
+
+// defaultStepName the filter name for set default value in rule string. eg: "trim|default:guest"
+const defaultStepName = "default"
+
+// DataView a read-only view of the filtering data, it is implemented by the Filtration.
+//
+// Safe(), String() and Int() only read the filtered values of the fields that
+// have been applied, the field without rule will be not found or zero value.
+// Get() will fall back to the raw value on the field is not filtered.
+type DataView interface {
+	// Raw get raw value by key
+	Raw(key string) (any, bool)
+	// Safe get filtered value by key
+	Safe(key string) (any, bool)
+	// Get value by key, will read the filtered value first, then the raw value.
+	Get(key string) (any, bool)
+	// String get a string value from filtered data.
+	String(key string) string
+	// Int get an int value from filtered data.
+	Int(key string) int
+}
+
+// FieldFunc cross-field filter func definition. it can compute the
+// field value from other fields by the data view.
+type FieldFunc func(val any, data DataView) (any, error)
+
+// filterStep a filter with parsed args in the rule
+type filterStep struct {
+	name string
+	args []string
+	// pre-resolved filter func. if is nil, will find filter by name on apply.
+	fn ContextFilterFunc
+	// cross-field filter func, see Rule.AddFieldFunc()
+	fieldFn FieldFunc
+	// condition name for the cond step, the args is condition args.
+	cond string
+	// filter steps of the cond step, only apply on condition is true.
+	group []*filterStep
+}
+
+// Rule definition
+type Rule struct {
+	// fields to filter
+	fields []string
+	// filter steps, args has been parsed.
+	steps []*filterStep
+	// user custom filter func
+	filterFunc func(val any) (any, error)
+	// default value for the rule
+	defaultVal any
+	// mode for use the default value
+	defaultMode DefaultMode
+	// only apply the rule on the func returns true
+	when func(val any, f *Filtration) bool
+	// check filter name is exists and the filter args
+	checkFn func(name string, args []string) error
+	// the rule has cross-field filter func
+	crossField bool
+	// priority of the rule, higher will be applied first
+	priority int
+	// the rule will be applied after the rules of the fields
+	after []string
+	// the field is required, cannot be missing or empty
+	required bool
+}
+
+func newRule(fields []string) *Rule {
+	return &Rule{fields: fields}
+}
+
+// parse the rule string and add filters. eg: "trim|str2arr:,"
+//
+// see ruleParser for the rule syntax.
+func (r *Rule) parse(rule string) error {
+	steps, err := parseRule(rule)
+	if err != nil {
+		return err
+	}
+
+	if len(steps) == 0 {
+		return errors.New("filter: invalid 'rule' params, cannot be empty")
+	}
+	return r.addSteps(steps)
+}
+
+// SetDefaultVal set default value for the rule
+func (r *Rule) SetDefaultVal(defaultVal any) *Rule {
+	r.defaultVal = defaultVal
+	return r
+}
+
+// SetDefaultMode set the mode for use the default value. see DefaultMode
+//
+// Usage:
+//
+//	f.AddRule("name", "trim").SetDefaultVal("guest").SetDefaultMode(filter.DefaultAfterFilter)
+func (r *Rule) SetDefaultMode(mode DefaultMode) *Rule {
+	r.defaultMode = mode
+	return r
+}
+
+// When set a condition func for the rule, the rule will be applied
+// to a field only when the func returns true.
+//
+// Usage:
+//
+//	f.AddRule("value", "int").When(func(val any, f *filter.Filtration) bool {
+//		return f.String("type") == "numeric"
+//	})
+func (r *Rule) When(fn func(val any, f *Filtration) bool) *Rule {
+	r.when = fn
+	return r
+}
+
+// Priority set the priority of the rule, the rule with higher priority
+// will be applied first. default is 0, the same priority rules are
+// applied in the added order.
+func (r *Rule) Priority(n int) *Rule {
+	r.priority = n
+	return r
+}
+
+// After set the rule will be applied after the other rules of the fields.
+// the field name should be same as the field of the other rule.
+//
+// It has higher precedence than the Priority(), on the dependencies
+// has cycle, Filtering() will return the ErrRuleCycle error.
+//
+// Usage:
+//
+//	// applied after the other rules of the "name"
+//	f.AddRule("name", "upper|substr:0,3").After("name")
+//	f.AddRule("name", "trim")
+func (r *Rule) After(fields ...string) *Rule {
+	r.after = append(r.after, fields...)
+	return r
+}
+
+// Required mark the field is required. on the field value is missing or
+// empty after use the default value, will return the ErrRequired error.
+//
+// Usage:
+//
+//	f.AddRule("name", "trim").Required()
+func (r *Rule) Required() *Rule {
+	r.required = true
+	return r
+}
+
+// SetFilterFunc user custom filter func.
+//
+// NOTE: the func will replace the filters of the rule. use AddFunc() to
+// combine the func with other filters.
+func (r *Rule) SetFilterFunc(fn func(val any) (any, error)) *Rule {
+	r.filterFunc = fn
+	return r
+}
+
+// AddFunc add a custom filter func as a step of the rule, it will be
+// applied in order with the filters. error will report as filter 'func'.
+//
+// Usage:
+//
+//	f.AddRule("age", "trim").AddFunc(func(val any) (any, error) {
+//		return strings.TrimPrefix(val.(string), "+"), nil
+//	}).AddFilters("int")
+func (r *Rule) AddFunc(fn func(val any) (any, error)) *Rule {
+	if fn == nil {
+		panic("filter: the filter func cannot be nil")
+	}
+
+	r.steps = append(r.steps, &filterStep{
+		name: funcStepName,
+		fn: func(_ context.Context, val any, _ []string) (any, error) {
+			return fn(val)
+		},
+	})
+	return r
+}
+
+// AddFieldFunc add a cross-field filter func as a step of the rule.
+// the func can read other fields by the data view.
+//
+// The rule will be applied after all the normal rules, so the data view can read
+// their filtered values. the cross-field rules are applied in the added order.
+// The field will be filtered even if it is missing, the val will be nil and
+// the filters before the first cross-field func are skipped.
+//
+// Usage:
+//
+//	f.AddRule("first,last", "trim")
+//	f.AddRule("full_name", "trim").AddFieldFunc(func(val any, data filter.DataView) (any, error) {
+//		if s, _ := val.(string); s != "" {
+//			return s, nil
+//		}
+//		return data.String("first") + " " + data.String("last"), nil
+//	})
+func (r *Rule) AddFieldFunc(fn FieldFunc) *Rule {
+	if fn == nil {
+		panic("filter: the filter func cannot be nil")
+	}
+
+	r.crossField = true
+	r.steps = append(r.steps, &filterStep{name: funcStepName, fieldFn: fn})
+	return r
+}
+
+// AddFilters add multi filter(s). will panic on the filter name is not
+// exists or the filter string is invalid.
+//
+// Usage:
+//
+//	r.AddFilters("int", "str2arr:,", `replace:"a,b",c`)
+func (r *Rule) AddFilters(filters ...string) *Rule {
+	if err := r.addFilters(filters...); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r *Rule) addFilters(filters ...string) error {
+	for _, filter := range filters {
+		steps, err := parseRule(filter)
+		if err != nil {
+			return err
+		}
+
+		if err = r.addSteps(steps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Rule) addSteps(steps []*filterStep) error {
+	if err := r.checkSteps(steps); err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		// set default value by rule string. eg: "trim|default:guest"
+		if step.name == defaultStepName && r.defaultVal == nil && len(step.args) > 0 {
+			r.defaultVal = step.args[0]
+		}
+		r.steps = append(r.steps, step)
+	}
+	return nil
+}
+
+// check filter and condition names and the filter args of the steps
+func (r *Rule) checkSteps(steps []*filterStep) error {
+	for _, step := range steps {
+		if step.cond != "" {
+			if err := checkCond(step.cond); err != nil {
+				return fmt.Errorf("filter: %w", err)
+			}
+			if err := r.checkSteps(step.group); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if r.checkFn != nil {
+			if err := r.checkFn(step.name, step.args); err != nil {
+				return fmt.Errorf("filter: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply rule for the rule fields
+//
+// Returns *FilterError on the option StopOnError is true,
+// otherwise will apply all fields and returns Errors.
+func (r *Rule) Apply(f *Filtration) error {
+	var es Errors
+
+	// validate field
+	for _, field := range r.Fields() {
+		paths := []string{field}
+		// expand wildcard path. eg: "items.*.name"
+		if strings.IndexByte(field, '*') > -1 {
+			paths = expandPath(f.data, field)
+		}
+
+		for _, path := range paths {
+			if fe := r.applyField(f, path); fe != nil {
+				// stop on error or the ctx is done
+				if f.opts.StopOnError || f.context().Err() != nil {
+					return fe
+				}
+				es = append(es, fe)
+			}
+		}
+	}
+
+	if len(es) > 0 {
+		return es
+	}
+	return nil
+}
+
+// contextError create the errors for the rule fields on the ctx is done before apply the rule.
+func (r *Rule) contextError(err error) Errors {
+	es := make(Errors, 0, len(r.fields))
+	for _, field := range r.fields {
+		es = append(es, &FilterError{Field: field, Filter: contextName, Err: err})
+	}
+	return es
+}
+
+// apply rule for the field, save the filtered value on success.
+func (r *Rule) applyField(f *Filtration, field string) *FilterError {
+	// get field value.
+	val, has := f.Get(field)
+	steps := r.steps
+	if r.useDefault(val, has) {
+		// no field. the cross-field rule can compute value from other fields.
+		if !has && r.defaultVal == nil && !r.crossField && !r.required {
+			return nil
+		}
+
+		// has default value
+		if r.defaultVal != nil {
+			val = r.defaultVal
+		} else if !has && r.crossField {
+			// the missing field is computed by the cross-field func, skip the filters before it.
+			steps = fieldFnSteps(steps)
+		}
+	}
+
+	// check the rule condition
+	if r.when != nil && !r.when(val, f) {
+		return nil
+	}
+
+	if r.required && isEmpty(val) {
+		return &FilterError{Field: field, Filter: requiredName, Value: val, Err: ErrRequired}
+	}
+
+	// custom filter func
+	if r.filterFunc != nil {
+		newVal, err := r.filterFunc(val)
+		if err != nil {
+			fe := newFilterError(funcStepName, val, nil, err)
+			fe.Field = field
+			return fe
+		}
+
+		// save filtered value.
+		f.setClean(field, r.defaultAfter(newVal))
+		return nil
+	}
+
+	// call built-in filters
+	return r.applyFilters(f, field, val, steps)
+}
+
+// fieldFnSteps get the steps start from the first cross-field func step.
+func fieldFnSteps(steps []*filterStep) []*filterStep {
+	for i, step := range steps {
+		if step.fieldFn != nil {
+			return steps[i:]
+		}
+	}
+	return nil
+}
+
+// check need to use default value before filtering
+func (r *Rule) useDefault(val any, has bool) bool {
+	if !has {
+		return true
+	}
+
+	switch r.defaultMode {
+	case DefaultOnNil:
+		return val == nil
+	case DefaultOnEmpty:
+		return isEmpty(val)
+	}
+	return false
+}
+
+// use default value on the filtered value is empty. for the mode DefaultAfterFilter
+func (r *Rule) defaultAfter(val any) any {
+	if r.defaultMode == DefaultAfterFilter && r.defaultVal != nil && isEmpty(val) {
+		return r.defaultVal
+	}
+	return val
+}
+
+// apply filters to the field value, save the filtered value on success.
+func (r *Rule) applyFilters(f *Filtration, field string, val any, steps []*filterStep) *FilterError {
+	val, fe := applySteps(f.context(), f, steps, val)
+	if fe != nil {
+		fe.Field = field
+		return fe
+	}
+
+	// save filtered value.
+	f.setClean(field, r.defaultAfter(val))
+	return nil
+}
+
+// applySteps apply the filter steps to the value. f can be nil.
+//
+// Will check the ctx is done before each step.
+func applySteps(ctx context.Context, f *Filtration, steps []*filterStep, val any) (any, *FilterError) {
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return nil, newFilterError(step.name, val, step.args, err)
+		}
+
+		newVal, err := step.apply(ctx, f, val)
+		if err != nil {
+			return nil, newFilterError(step.name, val, step.args, err)
+		}
+		val = newVal
+	}
+	return val, nil
+}
+
+// apply the filter step, will use the pre-resolved filter func first.
+func (s *filterStep) apply(ctx context.Context, f *Filtration, val any) (any, error) {
+	if s.cond != "" {
+		if !conditions[s.cond](val, s.args, f) {
+			return val, nil
+		}
+
+		newVal, fe := applySteps(ctx, f, s.group, val)
+		if fe != nil {
+			return nil, fe
+		}
+		return newVal, nil
+	}
+
+	if s.fn != nil {
+		return s.fn(ctx, val, s.args)
+	}
+	if s.fieldFn != nil {
+		return s.fieldFn(val, f)
+	}
+	if f == nil {
+		return ApplyContext(ctx, s.name, val, s.args)
+	}
+	return f.apply(ctx, s.name, val, s.args)
+}
+
+// resolve the filter funcs of the steps by the resolver.
+func resolveSteps(steps []*filterStep, resolver func(name string) ContextFilterFunc) {
+	for _, step := range steps {
+		if step.cond != "" {
+			resolveSteps(step.group, resolver)
+		} else if step.fn == nil && step.fieldFn == nil { // skip the custom func step
+			step.fn = resolver(step.name)
+		}
+	}
+}
+
+// Fields name get
+func (r *Rule) Fields() []string {
+	return r.fields
+}
+
+// check the rule has the field
+func (r *Rule) hasField(field string) bool {
+	for _, name := range r.fields {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package filter
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gookit/goutil/strutil"
+)
+
+// Schema is a compiled rule set. the rule string has been parsed,
+// and the filter funcs are pre-resolved.
+//
+// Schema is immutable after compiled, it is safe for concurrent use.
+//
+// Usage:
+//
+//	s := filter.MustCompile(map[string]string{
+//		"name": "trim|ucFirst",
+//		"age":  "trim|int",
+//	})
+//
+//	// in each request
+//	cleanData, err := s.Apply(data)
+type Schema struct {
+	opts  Options
+	rules []*Rule
+}
+
+// Compile the rules to a Schema. the rule format is same as the Filtration.AddRule()
+//
+// The filter names, condition names and filter args are checked on compile.
+// NOTE: the custom filters should be added to global before compile.
+func Compile(rules map[string]string, optFns ...func(opt *Options)) (*Schema, error) {
+	s := newSchema(optFns)
+	fields := make([]string, 0, len(rules))
+	for field := range rules {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if err := s.addRule(field, rules[field]); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func newSchema(optFns []func(opt *Options)) *Schema {
+	s := &Schema{
+		opts: Options{StopOnError: true, BindTag: DefaultBindTag},
+	}
+	for _, fn := range optFns {
+		fn(&s.opts)
+	}
+	return s
+}
+
+// MustCompile like Compile, but will panic on error.
+func MustCompile(rules map[string]string, optFns ...func(opt *Options)) *Schema {
+	s, err := Compile(rules, optFns...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *Schema) addRule(field, rule string) error {
+	fields := strutil.Split(field, ",")
+	if len(fields) == 0 {
+		return errEmptyFields
+	}
+
+	r := newRule(fields)
+	r.checkFn = s.checkFilter
+	if err := r.parse(rule); err != nil {
+		return err
+	}
+
+	// pre-resolve filter funcs
+	resolveSteps(r.steps, resolveFilter)
+
+	s.rules = append(s.rules, r)
+	return nil
+}
+
+func (s *Schema) checkFilter(name string, args []string) error {
+	return checkFilter(name, args, nil, s.opts.Lenient)
+}
+
+// New create a Filtration for the data, it will use the schema rules and options.
+//
+// The rules are shared with the schema, should not be modified.
+func (s *Schema) New(data map[string]any) *Filtration {
+	f := New(data)
+	*f.opts = s.opts
+	// limit cap, append rules will not change the schema rules.
+	f.filterRules = s.rules[:len(s.rules):len(s.rules)]
+	return f
+}
+
+// Apply filtering the data by the schema, returns the clean data.
+func (s *Schema) Apply(data map[string]any) (map[string]any, error) {
+	f := s.New(data)
+	err := f.Filtering()
+	return f.CleanData(), err
+}
+
+// ApplyContext filtering the data by the schema with context. see Filtration.FilteringContext()
+func (s *Schema) ApplyContext(ctx context.Context, data map[string]any) (map[string]any, error) {
+	f := s.New(data)
+	err := f.FilteringContext(ctx)
+	return f.CleanData(), err
+}
+
+// Options get a copy of the schema options
+func (s *Schema) Options() Options {
+	return s.opts
+}
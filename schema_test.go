@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestCompile(t *testing.T) {
+	is := assert.New(t)
+
+	s, err := Compile(map[string]string{
+		"name":    "trim|ucFirst",
+		"age":     "trim|int",
+		"tags":    "str2arr:;",
+		"items.*": "trim",
+		"nick":    "if:notEmpty(trim|upper)",
+	})
+	is.NoErr(err)
+	is.True(s.Options().StopOnError)
+
+	clean, err := s.Apply(map[string]any{
+		"name":  " inhere ",
+		"age":   " 23 ",
+		"tags":  "go;php",
+		"items": []any{" a ", " b"},
+		"nick":  " tom ",
+	})
+	is.NoErr(err)
+	is.Eq(map[string]any{
+		"name":  "Inhere",
+		"age":   23,
+		"tags":  []string{"go", "php"},
+		"items": []any{"a", "b"},
+		"nick":  "TOM",
+	}, clean)
+
+	// create filtration
+	f := s.New(map[string]any{"name": " tom ", "email": " A@B.COM "})
+	f.AddRule("email", "trim|lower")
+	is.NoErr(f.Filtering())
+	is.Eq("Tom", f.String("name"))
+	is.Eq("a@b.com", f.String("email"))
+	// schema rules not changed
+	is.Len(s.rules, 5)
+
+	_, err = s.Apply(map[string]any{"age": "abc"})
+	is.Err(err)
+
+	// invalid rules
+	_, err = Compile(map[string]string{"name": "trim|lowr"})
+	is.True(errors.Is(err, ErrUnknownFilter))
+	_, err = Compile(map[string]string{"name": "|"})
+	is.Err(err)
+	_, err = Compile(map[string]string{"": "trim"})
+	is.Err(err)
+	_, err = Compile(map[string]string{"name": "substr:1"})
+	is.ErrMsg(err, "filter: check 'substr' error: invalid filter args: expect 2 args, but given 1")
+	is.True(errors.Is(err, ErrBadArgs))
+	_, err = Compile(map[string]string{"name": "if:notEmpty(trim|lower:a)"})
+	is.True(errors.Is(err, ErrBadArgs))
+	is.Panics(func() {
+		MustCompile(map[string]string{"name": "trim|lowr"})
+	})
+
+	// apply with context
+	ctx, cancel := context.WithCancel(context.Background())
+	clean, err = s.ApplyContext(ctx, map[string]any{"name": " inhere "})
+	is.NoErr(err)
+	is.Eq("Inhere", clean["name"])
+	cancel()
+	_, err = s.ApplyContext(ctx, map[string]any{"name": " inhere "})
+	is.True(errors.Is(err, context.Canceled))
+
+	// lenient mode
+	s = MustCompile(map[string]string{"name": "trim|lowr"}, LenientMode)
+	clean, err = s.Apply(map[string]any{"name": " inhere "})
+	is.NoErr(err)
+	is.Eq("inhere", clean["name"])
+}
+
+func TestSchema_concurrent(t *testing.T) {
+	s := MustCompile(map[string]string{
+		"name":       "trim|upper",
+		"age":        "int",
+		"items.*.id": "int",
+	}, CollectErrors)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			num := strconv.Itoa(i)
+			clean, err := s.Apply(map[string]any{
+				"name":  " user" + num,
+				"age":   num,
+				"items": []any{map[string]any{"id": num}},
+			})
+
+			assert.NoErr(t, err)
+			assert.Eq(t, "USER"+num, clean["name"])
+			assert.Eq(t, i, clean["age"])
+			assert.Eq(t, []any{map[string]any{"id": i}}, clean["items"])
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,125 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// StructTag name for define filter rule on struct field.
+const StructTag = "filter"
+
+// Struct filtering the struct fields by the field tag "filter", will change the field value in place.
+//
+// Support nested, embedded struct, pointer and slice of struct fields.
+// The tag value is same as the rule string of the Filtration.AddRule(),
+// use "-" to ignore the field.
+//
+// Usage:
+//
+//	type User struct {
+//		Name  string   `filter:"trim|ucFirst"`
+//		Email string   `filter:"trim|lower"`
+//		Tags  []string `filter:"trimStrings"`
+//	}
+//
+//	u := &User{Name: " inhere ", Email: " Some@Email.com "}
+//	err := filter.Struct(u)
+func Struct(ptr any) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("filter: Struct() requires a non-nil struct pointer, given %T", ptr)
+	}
+
+	return filterStruct(rv.Elem(), "")
+}
+
+func filterStruct(rv reflect.Value, path string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+
+		tag := sf.Tag.Get(StructTag)
+		if tag == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		field := path + sf.Name
+		if tag != "" && fv.CanSet() {
+			if err := filterStructField(fv, field, tag); err != nil {
+				return err
+			}
+		}
+
+		if err := filterNested(fv, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filter the nested struct, pointer and slice of struct.
+func filterNested(fv reflect.Value, field string) error {
+	switch fv.Kind() {
+	case reflect.Ptr:
+		if !fv.IsNil() {
+			return filterNested(fv.Elem(), field)
+		}
+	case reflect.Struct:
+		if fv.Type() != timeType {
+			return filterStruct(fv, field+".")
+		}
+	case reflect.Slice, reflect.Array:
+		elemTyp := fv.Type().Elem()
+		if elemTyp.Kind() == reflect.Ptr {
+			elemTyp = elemTyp.Elem()
+		}
+		if elemTyp.Kind() != reflect.Struct {
+			return nil
+		}
+
+		for i := 0; i < fv.Len(); i++ {
+			if err := filterNested(fv.Index(i), field+"."+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// apply the tag rule for the struct field
+func filterStructField(fv reflect.Value, field, rule string) error {
+	// skip nil pointer, same as missing field
+	if fv.Kind() == reflect.Ptr && fv.IsNil() {
+		return nil
+	}
+
+	r := newRule([]string{field})
+	r.checkFn = func(name string, args []string) error {
+		return checkFilter(name, args, nil, false)
+	}
+
+	val := fv.Interface()
+	if err := r.parse(rule); err != nil {
+		return &FilterError{Field: field, Filter: rule, Value: val, Err: err}
+	}
+
+	val, fe := applySteps(context.Background(), nil, r.steps, val)
+	if fe != nil {
+		fe.Field = field
+		return fe
+	}
+
+	nv, err := convType(val, fv.Type())
+	if err != nil {
+		return &FilterError{Field: field, Filter: rule, Value: val, Err: fmt.Errorf("%w: %s", ErrTypeMismatch, err.Error())}
+	}
+
+	fv.Set(nv)
+	return nil
+}
@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+type testAddress struct {
+	City string `filter:"trim|upperWord"`
+	Zip  string `filter:"trim"`
+}
+
+type testBase struct {
+	ID string `filter:"trim|int"`
+}
+
+type testUser struct {
+	testBase
+	Name     string   `filter:"trim|ucFirst"`
+	Email    string   `filter:"trim|lower"`
+	Nick     *string  `filter:"trim"`
+	Tags     []string `filter:"trimStrings"`
+	Age      int      `filter:"-"`
+	Birthday time.Time
+	Home     testAddress
+	Work     *testAddress
+	Others   []*testAddress
+	Raw      string
+	private  string `filter:"trim"`
+}
+
+func TestStruct(t *testing.T) {
+	is := assert.New(t)
+
+	nick := " tom "
+	u := &testUser{
+		testBase: testBase{ID: " 23 "},
+		Name:     " inhere ",
+		Email:    " Some@Email.COM ",
+		Nick:     &nick,
+		Tags:     []string{" a", "b "},
+		Home:     testAddress{City: " new york ", Zip: " 123 "},
+		Work:     &testAddress{City: " shen zhen "},
+		Others:   []*testAddress{{City: " a b "}, nil},
+		Raw:      " raw ",
+		private:  " private ",
+	}
+
+	is.NoErr(Struct(u))
+	is.Eq("23", u.ID)
+	is.Eq("Inhere", u.Name)
+	is.Eq("some@email.com", u.Email)
+	is.Eq("tom", *u.Nick)
+	is.Eq([]string{"a", "b"}, u.Tags)
+	is.Eq("New York", u.Home.City)
+	is.Eq("123", u.Home.Zip)
+	is.Eq("Shen Zhen", u.Work.City)
+	is.Eq("A B", u.Others[0].City)
+	is.Eq(" raw ", u.Raw)
+	is.Eq(" private ", u.private)
+
+	// error
+	is.Err(Struct(nil))
+	is.Err(Struct(*u))
+
+	err := Struct(&struct {
+		Age string `filter:"int"`
+	}{Age: "abc"})
+	var fe *FilterError
+	is.True(errors.As(err, &fe))
+	is.Eq("Age", fe.Field)
+	is.Eq("int", fe.Filter)
+
+	err = Struct(&struct {
+		Items []testAddress
+		Age   int `filter:"trim"`
+	}{Items: []testAddress{{City: "a"}}})
+	is.True(errors.Is(err, ErrTypeMismatch))
+
+	err = Struct(&struct {
+		Items []struct {
+			Name string `filter:"lowr"`
+		}
+	}{Items: []struct {
+		Name string `filter:"lowr"`
+	}{{Name: "a"}}})
+	is.True(errors.Is(err, ErrUnknownFilter))
+	is.True(errors.As(err, &fe))
+	is.Eq("Items.0.Name", fe.Field)
+}
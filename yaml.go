@@ -0,0 +1,274 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine a non-empty line of the YAML document, the comment has been removed.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// yamlParser is a simple YAML subset parser, it is enough for the schema file.
+//
+// Supported:
+//
+//	# comment
+//	key: value
+//	"quoted key": 'quoted value'
+//	mapping:
+//	  key: value
+//	list:
+//	  - item
+//	  - "item"
+//
+// The scalar value can be a string, int, float, bool(true, false) or null(null, ~).
+// The flow collections(eg: [a, b]), anchors, tags and the block scalars are not supported.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML parse the YAML mapping document, returns the top-level keys in
+// the document order and the data. the nested mapping is map[string]any,
+// the list is []any.
+func parseYAML(src string) (keys []string, data map[string]any, err error) {
+	p := &yamlParser{}
+	if err = p.scan(src); err != nil {
+		return nil, nil, err
+	}
+
+	data = make(map[string]any)
+	if len(p.lines) == 0 {
+		return nil, data, nil
+	}
+
+	keys, data, err = p.parseMap(p.lines[0].indent)
+	if err == nil && p.pos < len(p.lines) {
+		err = p.errorf(p.lines[p.pos], "unexpected indentation")
+	}
+	return
+}
+
+// scan split the source to lines, will skip the empty and comment lines.
+func (p *yamlParser) scan(src string) error {
+	for i, text := range strings.Split(src, "\n") {
+		text = strings.TrimRight(stripComment(text), " \t\r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" {
+			continue
+		}
+
+		ln := yamlLine{num: i + 1, indent: len(text) - len(trimmed), text: trimmed}
+		if trimmed[0] == '\t' {
+			return p.errorf(ln, "tab cannot be used for indentation")
+		}
+		// document start or end marker
+		if ln.indent == 0 && (trimmed == "---" || trimmed == "...") {
+			continue
+		}
+		p.lines = append(p.lines, ln)
+	}
+	return nil
+}
+
+func (p *yamlParser) parseMap(indent int) ([]string, map[string]any, error) {
+	var keys []string
+	mp := make(map[string]any)
+
+	for p.pos < len(p.lines) {
+		ln := p.lines[p.pos]
+		if ln.indent < indent {
+			break
+		}
+		if ln.indent > indent {
+			return nil, nil, p.errorf(ln, "unexpected indentation")
+		}
+		if isListItem(ln.text) {
+			return nil, nil, p.errorf(ln, "unexpected list item in the mapping")
+		}
+
+		key, rest, err := splitYAMLKey(ln.text)
+		if err != nil {
+			return nil, nil, p.errorf(ln, err.Error())
+		}
+		if _, ok := mp[key]; ok {
+			return nil, nil, p.errorf(ln, fmt.Sprintf("duplicate key '%s'", key))
+		}
+		p.pos++
+
+		var val any
+		if rest != "" {
+			if val, err = parseYAMLScalar(rest); err != nil {
+				return nil, nil, p.errorf(ln, err.Error())
+			}
+		} else if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			switch {
+			// the list can have same indent as the key
+			case isListItem(next.text) && next.indent >= indent:
+				val, err = p.parseList(next.indent)
+			case next.indent > indent:
+				_, val, err = p.parseMap(next.indent)
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		keys = append(keys, key)
+		mp[key] = val
+	}
+	return keys, mp, nil
+}
+
+func (p *yamlParser) parseList(indent int) ([]any, error) {
+	var list []any
+	for p.pos < len(p.lines) {
+		ln := p.lines[p.pos]
+		if ln.indent != indent || !isListItem(ln.text) {
+			break
+		}
+
+		val, err := parseYAMLScalar(strings.TrimLeft(ln.text[1:], " "))
+		if err != nil {
+			return nil, p.errorf(ln, err.Error())
+		}
+
+		p.pos++
+		if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+			return nil, p.errorf(p.lines[p.pos], "nested value in the list item is not supported")
+		}
+		list = append(list, val)
+	}
+	return list, nil
+}
+
+func (p *yamlParser) errorf(ln yamlLine, msg string) error {
+	return fmt.Errorf("filter: parse YAML error at line %d: %s", ln.num, msg)
+}
+
+func isListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey split the "key: value" line to key and value.
+func splitYAMLKey(text string) (key, rest string, err error) {
+	if ch := text[0]; ch == '"' || ch == '\'' {
+		end := quotedEnd(text)
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated quoted key")
+		}
+
+		if key, err = unquoteYAML(text[:end+1]); err != nil {
+			return "", "", err
+		}
+		if rest = text[end+1:]; rest == ":" || strings.HasPrefix(rest, ": ") {
+			return key, strings.TrimSpace(rest[1:]), nil
+		}
+		return "", "", fmt.Errorf("expect ':' after the key")
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			if key = strings.TrimSpace(text[:i]); key == "" {
+				break
+			}
+			return key, strings.TrimSpace(text[i+1:]), nil
+		}
+	}
+	return "", "", fmt.Errorf("expect 'key: value', but got %q", text)
+}
+
+// parseYAMLScalar parse the scalar value. eg: string, number, bool and null
+func parseYAMLScalar(s string) (any, error) {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+
+	switch s[0] {
+	case '"', '\'':
+		if end := quotedEnd(s); end != len(s)-1 {
+			return nil, fmt.Errorf("invalid quoted value %s", s)
+		}
+		return unquoteYAML(s)
+	case '[', '{', '|', '>', '&', '*', '!':
+		return nil, fmt.Errorf("unsupported YAML syntax %q", s)
+	}
+
+	// number must start with digit. eg: 12, -1.5, .5
+	if c := strings.TrimLeft(s, "+-"); c != "" && (c[0] >= '0' && c[0] <= '9' || c[0] == '.') {
+		if iv, err := strconv.Atoi(s); err == nil {
+			return iv, nil
+		}
+		if fv, err := strconv.ParseFloat(s, 64); err == nil {
+			return fv, nil
+		}
+	}
+	return s, nil
+}
+
+// quotedEnd find the end index of the quoted string at s[0]. returns -1 on not found.
+func quotedEnd(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			// '' is the escaped single quote
+			if quote == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+func unquoteYAML(s string) (string, error) {
+	if s[0] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+
+	str, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid quoted value %s", s)
+	}
+	return str, nil
+}
+
+// stripComment remove the comment of the line, the '#' in quoted string
+// or not after a space is not the comment. eg: "trim:#"
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote == '"' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			// the quote only at the start of the value
+			if i == 0 || line[i-1] == ' ' || line[i-1] == '-' {
+				quote = ch
+			}
+		case ch == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParseYAML(t *testing.T) {
+	is := assert.New(t)
+
+	keys, data, err := parseYAML(`---
+# comment
+name: trim|str2arr:, # comment
+"a: b": 'it''s'
+num: 12
+float: -1.5
+flag: true
+none: ~
+str: "a # b\n"
+map:
+  key: value
+  sub:
+    - a
+    - 'b'
+list:
+- 1
+- x#y
+empty:
+`)
+	is.NoErr(err)
+	is.Eq([]string{"name", "a: b", "num", "float", "flag", "none", "str", "map", "list", "empty"}, keys)
+	is.Eq("trim|str2arr:,", data["name"])
+	is.Eq("it's", data["a: b"])
+	is.Eq(12, data["num"])
+	is.Eq(-1.5, data["float"])
+	is.Eq(true, data["flag"])
+	is.Nil(data["none"])
+	is.Eq("a # b\n", data["str"])
+	is.Eq(map[string]any{"key": "value", "sub": []any{"a", "b"}}, data["map"])
+	is.Eq([]any{1, "x#y"}, data["list"])
+	is.Nil(data["empty"])
+
+	keys, data, err = parseYAML("# only comment\n")
+	is.NoErr(err)
+	is.Empty(keys)
+	is.Empty(data)
+
+	tests := []struct {
+		src string
+		err string
+	}{
+		{"a: 1\n  b: 2", "line 2: unexpected indentation"},
+		{"a:\n  b: 1\n c: 2", "line 3: unexpected indentation"},
+		{"a: 1\na: 2", "line 2: duplicate key 'a'"},
+		{"- a", "line 1: unexpected list item in the mapping"},
+		{"a:\n  - b\n    c: 1", "line 3: nested value in the list item is not supported"},
+		{"a: [b, c]", "line 1: unsupported YAML syntax"},
+		{"a: |", "unsupported YAML syntax"},
+		{"a: 'b", "line 1: invalid quoted value"},
+		{"'a: 1", "line 1: unterminated quoted key"},
+		{"'a' 1", "line 1: expect ':' after the key"},
+		{"a:\n\tb: 1", "line 2: tab cannot be used for indentation"},
+	}
+	for _, tt := range tests {
+		_, _, err = parseYAML(tt.src)
+		is.ErrSubMsg(err, tt.err)
+	}
+}